@@ -8,6 +8,8 @@ import (
 	"syscall"
 
 	gapi "github.com/Iowel/app-auth-service/internal/delivery"
+	"github.com/Iowel/app-auth-service/internal/pkg/jwtauth"
+	"github.com/Iowel/app-auth-service/internal/pkg/policy"
 	"github.com/Iowel/app-auth-service/internal/pkg/worker"
 	"github.com/Iowel/app-auth-service/internal/repository/postgres"
 	"github.com/Iowel/app-auth-service/internal/service"
@@ -15,6 +17,7 @@ import (
 	"github.com/Iowel/app-auth-service/pkg/configs"
 	"github.com/Iowel/app-auth-service/pkg/eventbus"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/sync/errgroup"
@@ -34,6 +37,12 @@ const (
 func main() {
 	cfg := configs.LoadConfig()
 
+	// SECRET - ключ шифрования секретов в базе и HMAC токена формы согласия OAuth;
+	// с пустым ключом этот токен может посчитать кто угодно
+	if cfg.Auth.Secret == "" {
+		log.Fatal("SECRET is not set")
+	}
+
 	ctx, cancel := signal.NotifyContext(context.Background(), interruptSignals...)
 	defer cancel()
 
@@ -50,13 +59,85 @@ func main() {
 	// repository
 	userRepo := postgres.NewUserRepo(db)
 	tokenRepo := postgres.NewTokenRepository(db)
+	sessionRepo := postgres.NewSessionRepo(db)
 	mailRepo := postgres.NewEmailRepository(db)
 	cacheRepo := cache.NewRedisCache(cfg.Redis.Port, redisDB, exp)
+	loginAttempts := cache.NewLoginAttempts(cfg.Redis.Port, redisDB)
+	rateLimiter := cache.NewRateLimiter(cfg.Redis.Port, redisDB)
+	permissionCache := cache.NewPermissionCache(cfg.Redis.Port, redisDB, service.PermissionCacheTTL)
 	statRepo := postgres.NewStatRepository(db)
 
+	keyRepo := postgres.NewKeyRepo(db)
+	oauthRepo := postgres.NewOAuthRepo(db)
+	twoFactorRepo := postgres.NewTwoFactorRepo(db)
+	passkeyRepo := postgres.NewPasskeyRepo(db)
+	passwordResetRepo := postgres.NewPasswordResetRepo(db)
+	rbacRepo := postgres.NewRBACRepo(db)
+	policyRepo := postgres.NewPolicyRepo(db)
+	appRepo := postgres.NewAppRepo(db)
+	patRepo := postgres.NewPersonalAccessTokenRepo(db)
+
+	// ключи подписи нужны для JWT access токенов (TOKEN_FORMAT=jwt) и для ID токенов OpenID Connect (TOKEN_ISSUER)
+	var signer *jwtauth.Manager
+	if cfg.Auth.TokenFormat == configs.TokenFormatJWT || cfg.Auth.Issuer != "" {
+		signer = jwtauth.NewManager(cfg.Auth.Issuer)
+	}
+
+	var accessTokenSigner *jwtauth.Manager
+	if cfg.Auth.TokenFormat == configs.TokenFormatJWT {
+		accessTokenSigner = signer
+	}
+
+	lockoutPolicy := service.LockoutPolicy{
+		MaxFailures:      cfg.Auth.LoginMaxFailures,
+		MaxFailuresPerIP: cfg.Auth.LoginMaxFailuresPerIP,
+		LockoutDuration:  cfg.Auth.LoginLockoutDuration,
+	}
+
 	// service
-	authServ := service.NewAuthService(userRepo, tokenRepo, cacheRepo, eventBus)
+	authServ := service.NewAuthService(userRepo, tokenRepo, cacheRepo, eventBus, service.AuthServiceDeps{
+		AppRepo:       appRepo,
+		SessionRepo:   sessionRepo,
+		PATRepo:       patRepo,
+		TwoFactorRepo: twoFactorRepo,
+		ResetRepo:     passwordResetRepo,
+		MailRepo:      mailRepo,
+		Attempts:      loginAttempts,
+		Lockout:       lockoutPolicy,
+		Signer:        accessTokenSigner,
+		Secret:        cfg.Auth.Secret,
+		TOTPIssuer:    cfg.Auth.TOTPIssuer,
+	})
 	mailServ := service.NewMailService(userRepo, mailRepo)
+	keyServ := service.NewKeyService(keyRepo, signer, cfg.Auth.Secret)
+	oauthServ := service.NewOAuthService(oauthRepo, userRepo, tokenRepo, authServ, signer)
+
+	// passkeys включаются заданием WEBAUTHN_RP_ID
+	var webAuthn *webauthn.WebAuthn
+	if cfg.WebAuthn.RPID != "" {
+		webAuthn, err = service.NewWebAuthn(cfg.WebAuthn.RPID, cfg.WebAuthn.RPDisplayName, cfg.WebAuthn.RPOrigins)
+		if err != nil {
+			log.Fatalf("Failed to configure WebAuthn: %v", err)
+		}
+	}
+	passkeyServ := service.NewPasskeyService(passkeyRepo, userRepo, authServ, webAuthn)
+	rbacServ := service.NewRBACService(rbacRepo, permissionCache, cacheRepo)
+
+	policyEngine, err := policy.NewEngine()
+	if err != nil {
+		log.Fatalf("Failed to create policy engine: %v", err)
+	}
+	policyServ := service.NewPolicyService(policyRepo, userRepo, rbacServ, policyEngine)
+
+	err = keyServ.Init(ctx)
+	if err != nil {
+		log.Fatalf("Failed to init signing keys: %v", err)
+	}
+
+	err = policyServ.Reload(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load policies: %v", err)
+	}
 	statServ := service.NewStatService(eventBus, statRepo)
 
 	// redis connection
@@ -69,10 +150,20 @@ func main() {
 
 	// servers
 	worker.RunTaskProcessor(ctx, waitGroup, cfg, redisOpt, db)
-	gapi.RunGrpcServer(ctx, waitGroup, cfg, db, authServ, mailServ, taskDistributor)
-	gapi.RunGatewayServer(ctx, waitGroup, authServ, mailServ, cfg, db, taskDistributor)
+	serverDeps := gapi.ServerDeps{
+		KeyService:     keyServ,
+		OAuthService:   oauthServ,
+		PasskeyService: passkeyServ,
+		RBACService:    rbacServ,
+		PolicyService:  policyServ,
+		RateLimiter:    rateLimiter,
+	}
+	gapi.RunGrpcServer(ctx, waitGroup, cfg, db, authServ, mailServ, taskDistributor, serverDeps)
+	gapi.RunGatewayServer(ctx, waitGroup, authServ, mailServ, cfg, db, taskDistributor, serverDeps)
 
 	statServ.RegisterEvent(ctx, waitGroup)
+	keyServ.RunReloader(ctx, waitGroup)
+	policyServ.RunReloader(ctx, waitGroup)
 
 	err = waitGroup.Wait()
 	if err != nil {
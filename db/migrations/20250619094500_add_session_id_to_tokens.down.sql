@@ -0,0 +1,5 @@
+ALTER TABLE refresh_tokens
+DROP COLUMN IF EXISTS session_id;
+
+ALTER TABLE tokens
+DROP COLUMN IF EXISTS session_id;
@@ -0,0 +1,7 @@
+ALTER TABLE tokens
+ADD COLUMN session_id UUID REFERENCES sessions(id) ON DELETE CASCADE;
+
+ALTER TABLE refresh_tokens
+ADD COLUMN session_id UUID REFERENCES sessions(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS tokens_session_id_idx ON tokens (session_id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS signing_keys (
+    kid TEXT PRIMARY KEY,
+    algorithm TEXT NOT NULL,
+    private_key BYTEA NOT NULL,
+    public_key BYTEA NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- новый ключ сначала только публикуется в JWKS, активируется в этот момент
+    activates_at TIMESTAMPTZ,
+    retired_at TIMESTAMPTZ
+);
+
+-- активный ключ может быть только один
+CREATE UNIQUE INDEX IF NOT EXISTS signing_keys_active_idx ON signing_keys (is_active) WHERE is_active;
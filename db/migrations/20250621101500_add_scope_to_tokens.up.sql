@@ -0,0 +1,2 @@
+ALTER TABLE tokens
+ADD COLUMN scope TEXT NOT NULL DEFAULT 'authentication';
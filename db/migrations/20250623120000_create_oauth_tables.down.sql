@@ -0,0 +1,8 @@
+ALTER TABLE sessions DROP COLUMN IF EXISTS client_id;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS scope;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS client_id;
+ALTER TABLE tokens DROP COLUMN IF EXISTS client_id;
+
+DROP TABLE IF EXISTS "oauth_consents";
+DROP TABLE IF EXISTS "oauth_authorization_codes";
+DROP TABLE IF EXISTS "oauth_clients";
\ No newline at end of file
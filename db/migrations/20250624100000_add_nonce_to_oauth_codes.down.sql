@@ -0,0 +1,2 @@
+ALTER TABLE oauth_authorization_codes
+DROP COLUMN IF EXISTS nonce;
\ No newline at end of file
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS webauthn_credentials (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id BYTEA NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    attestation_type TEXT NOT NULL DEFAULT '',
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    -- сырые флаги authenticator data (UV, BE, BS)
+    flags SMALLINT NOT NULL DEFAULT 0,
+    aaguid BYTEA,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    name TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS webauthn_credentials_user_id_idx ON webauthn_credentials (user_id);
+
+-- состояние церемонии между Begin* и Finish*: challenge и параметры, которые мы отдали браузеру
+CREATE TABLE IF NOT EXISTS webauthn_ceremonies (
+    id BIGSERIAL PRIMARY KEY,
+    token_hash BYTEA NOT NULL UNIQUE,
+    -- для логина юзверь заранее неизвестен
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    session_data JSONB NOT NULL,
+    is_used BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expired_at TIMESTAMPTZ NOT NULL
+);
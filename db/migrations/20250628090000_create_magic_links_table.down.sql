@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "magic_links";
\ No newline at end of file
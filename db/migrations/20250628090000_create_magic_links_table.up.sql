@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS magic_links (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR NOT NULL,
+    -- sha256 кода из ссылки
+    code_hash BYTEA NOT NULL,
+    -- браузер, из которого запросили ссылку; пусто - не привязываем
+    user_agent TEXT NOT NULL DEFAULT '',
+    is_used BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expired_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS roles (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id BIGSERIAL PRIMARY KEY,
+    -- вида "keys.rotate"; "*" - все права
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id BIGINT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id BIGINT NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id BIGINT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_roles_role_id_idx ON user_roles (role_id);
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'все права'),
+    ('moderator', 'модерация пользователей')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+    ('*', 'все права'),
+    ('roles.manage', 'управление ролями и правами'),
+    ('keys.rotate', 'ротация ключей подписи'),
+    ('accounts.unlock', 'снятие блокировки входа'),
+    ('users.moderate', 'модерация пользователей')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON (r.name = 'admin' AND p.name = '*')
+    OR (r.name = 'moderator' AND p.name = 'users.moderate')
+ON CONFLICT DO NOTHING;
+
+-- переносим роли, которые до этого хранились прямо в users.role;
+-- дальше users.role держит в синхроне с user_roles сам rbac_repo
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id
+FROM users u
+JOIN roles r ON r.name = u.role
+ON CONFLICT DO NOTHING;
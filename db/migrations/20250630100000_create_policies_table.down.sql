@@ -0,0 +1,2 @@
+DELETE FROM "permissions" WHERE name = 'policies.evaluate';
+DROP TABLE IF EXISTS "policies";
\ No newline at end of file
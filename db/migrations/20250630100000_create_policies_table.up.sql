@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS policies (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    -- "profile.edit", "wallet.*" или "*"
+    action TEXT NOT NULL,
+    effect TEXT NOT NULL CHECK (effect IN ('allow', 'deny')),
+    -- CEL выражение над subject, resource, attributes, action и now; должно вернуть bool
+    expression TEXT NOT NULL,
+    -- больше - проверяется раньше
+    priority INT NOT NULL DEFAULT 0,
+    is_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('policies.evaluate', 'проверка политик от имени других юзверей')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO policies (name, description, action, effect, expression) VALUES
+    ('wallet-requires-verified-email', 'операции с кошельком только с подтвержденным email',
+        'wallet.*', 'deny', '!subject.is_email_verified'),
+    ('profile-edit-self', 'свой профиль редактировать можно всегда',
+        'profile.edit', 'allow', 'resource.type == "user" && resource.id == subject.id')
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS apps (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- все существующие юзвери попадают в приложение по умолчанию
+INSERT INTO apps (id, name) VALUES (1, 'default') ON CONFLICT DO NOTHING;
+SELECT setval('apps_id_seq', (SELECT MAX(id) FROM apps));
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS app_id BIGINT NOT NULL DEFAULT 1 REFERENCES apps(id);
+
+-- email уникален только внутри приложения
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+ALTER TABLE users ADD CONSTRAINT users_app_id_email_key UNIQUE (app_id, email);
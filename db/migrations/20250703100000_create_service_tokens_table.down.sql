@@ -0,0 +1,4 @@
+DELETE FROM "permissions" WHERE name = 'clients.manage';
+DROP TABLE IF EXISTS "service_tokens";
+ALTER TABLE oauth_clients DROP COLUMN IF EXISTS secret_rotated_at;
+ALTER TABLE oauth_clients DROP COLUMN IF EXISTS is_service;
\ No newline at end of file
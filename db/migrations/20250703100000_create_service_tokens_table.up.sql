@@ -0,0 +1,18 @@
+-- машинные клиенты получают токены по client_credentials, юзверя у них нет
+ALTER TABLE oauth_clients ADD COLUMN IF NOT EXISTS is_service BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE oauth_clients ADD COLUMN IF NOT EXISTS secret_rotated_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS service_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    token_hash BYTEA NOT NULL UNIQUE,
+    client_id TEXT NOT NULL REFERENCES oauth_clients(client_id) ON DELETE CASCADE,
+    scope TEXT NOT NULL DEFAULT '',
+    expiry TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS service_tokens_client_id_idx ON service_tokens (client_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('clients.manage', 'регистрация машинных клиентов и смена их секретов')
+ON CONFLICT (name) DO NOTHING;
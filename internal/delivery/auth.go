@@ -0,0 +1,201 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/service"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+	pbv2 "github.com/Iowel/app-auth-service/pkg/pb/v2"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type accessLevel int
+
+const (
+	// без токена: логин, регистрация, восстановление доступа
+	accessPublic accessLevel = iota
+	// токен юзверя: сессия или personal access token
+	accessUser
+	// токен юзверя или сервиса (client_credentials)
+	accessUserOrService
+)
+
+// кого пускаем в метод; role и permission проверяются через RBAC поверх accessUser.
+// scope - что нужно personal access token'у: пусто - PAT в метод не пускаем, scopeAny - пускаем любой
+type methodPolicy struct {
+	access     accessLevel
+	role       string
+	permission string
+	scope      string
+}
+
+const scopeAny = "*"
+
+var (
+	public        = methodPolicy{access: accessPublic}
+	authenticated = methodPolicy{access: accessUser}
+	// проверка токена и прав: то, ради чего PAT и выпускают
+	anyToken = methodPolicy{access: accessUser, scope: scopeAny}
+)
+
+// PAT в админскую ручку пускаем, только если юзверь явно выдал ему scope с именем права
+func requirePermission(permission string) methodPolicy {
+	return methodPolicy{access: accessUser, permission: permission, scope: permission}
+}
+
+func requireScope(scope string) methodPolicy {
+	return methodPolicy{access: accessUser, scope: scope}
+}
+
+// методы, которых здесь нет, требуют токен юзверя и закрыты для PAT: смена пароля, 2FA, passkeys, сессии
+var methodPolicies = map[string]methodPolicy{
+	pb.AuthService_RegisterUser_FullMethodName:         public,
+	pb.AuthService_LoginUser_FullMethodName:            public,
+	pb.AuthService_RefreshToken_FullMethodName:         public,
+	pb.AuthService_VerifyEmail_FullMethodName:          public,
+	pb.AuthService_VerifyTwoFactor_FullMethodName:      public,
+	pb.AuthService_BeginPasskeyLogin_FullMethodName:    public,
+	pb.AuthService_FinishPasskeyLogin_FullMethodName:   public,
+	pb.AuthService_RequestPasswordReset_FullMethodName: public,
+	pb.AuthService_ResetPassword_FullMethodName:        public,
+	pb.AuthService_RequestMagicLink_FullMethodName:     public,
+	pb.AuthService_ConsumeMagicLink_FullMethodName:     public,
+
+	pb.AuthService_VerifyToken_FullMethodName: {access: accessUserOrService, scope: scopeAny},
+	pb.AuthService_VerifyRole_FullMethodName:  anyToken,
+	pb.AuthService_Authorize_FullMethodName:   anyToken,
+
+	pb.AuthService_ListSessions_FullMethodName:             requireScope(domain.ScopeAccountRead),
+	pb.AuthService_ListPersonalAccessTokens_FullMethodName: requireScope(domain.ScopeAccountRead),
+
+	pb.AuthService_RotateSigningKey_FullMethodName:          requirePermission(domain.PermissionKeysRotate),
+	pb.AuthService_UnlockAccount_FullMethodName:             requirePermission(domain.PermissionAccountsUnlock),
+	pb.AuthService_ListRoles_FullMethodName:                 requirePermission(domain.PermissionRolesManage),
+	pb.AuthService_CreateRole_FullMethodName:                requirePermission(domain.PermissionRolesManage),
+	pb.AuthService_DeleteRole_FullMethodName:                requirePermission(domain.PermissionRolesManage),
+	pb.AuthService_GrantRolePermission_FullMethodName:       requirePermission(domain.PermissionRolesManage),
+	pb.AuthService_RevokeRolePermission_FullMethodName:      requirePermission(domain.PermissionRolesManage),
+	pb.AuthService_AssignUserRole_FullMethodName:            requirePermission(domain.PermissionRolesManage),
+	pb.AuthService_RevokeUserRole_FullMethodName:            requirePermission(domain.PermissionRolesManage),
+	pb.AuthService_GetUserPermissions_FullMethodName:        requirePermission(domain.PermissionRolesManage),
+	pb.AuthService_RegisterServiceClient_FullMethodName:     requirePermission(domain.PermissionClientsManage),
+	pb.AuthService_RotateServiceClientSecret_FullMethodName: requirePermission(domain.PermissionClientsManage),
+
+	pbv2.AuthService_RegisterUser_FullMethodName: public,
+	pbv2.AuthService_LoginUser_FullMethodName:    public,
+	pbv2.AuthService_VerifyToken_FullMethodName:  {access: accessUserOrService, scope: scopeAny},
+	pbv2.AuthService_VerifyRole_FullMethodName:   anyToken,
+}
+
+type authenticator struct {
+	authService service.IAuthService
+	rbacService service.IRBACService
+	policies    map[string]methodPolicy
+}
+
+func newAuthenticator(authService service.IAuthService, rbacService service.IRBACService) *authenticator {
+	return &authenticator{
+		authService: authService,
+		rbacService: rbacService,
+		policies:    methodPolicies,
+	}
+}
+
+// unary интерцептор: один раз проверяет токен и кладет юзверя или сервис в контекст
+func NewAuthInterceptor(authService service.IAuthService, rbacService service.IRBACService) grpc.UnaryServerInterceptor {
+	a := newAuthenticator(authService, rbacService)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func NewAuthStreamInterceptor(authService service.IAuthService, rbacService service.IRBACService) grpc.StreamServerInterceptor {
+	a := newAuthenticator(authService, rbacService)
+
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// у стрима контекст не подменить, поэтому оборачиваем его
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	const op = "delivery.auth.authenticate"
+
+	policy, ok := a.policies[method]
+	if !ok {
+		policy = authenticated
+	}
+	if policy.access == accessPublic {
+		return ctx, nil
+	}
+
+	principal, err := a.authService.AuthenticateToken(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+	}
+
+	if principal.User == nil {
+		if policy.access != accessUserOrService {
+			return nil, status.Errorf(codes.PermissionDenied, "service tokens are not allowed for this method")
+		}
+		return service.ContextWithPrincipal(ctx, principal), nil
+	}
+
+	if principal.Scope == service.ScopePersonalAccess && !policy.allowsPersonalAccessToken(principal.Scopes) {
+		return nil, status.Errorf(codes.PermissionDenied, "personal access token is not allowed for this method")
+	}
+
+	if policy.role != "" || policy.permission != "" {
+		err := a.rbacService.Authorize(ctx, principal.User.Id, policy.role, policy.permission)
+		if err != nil {
+			if errors.Is(err, domain.ErrPermissionDenied) {
+				return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+			}
+			log.Printf("Authorization failed: path: %s, error: %v", op, err)
+			return nil, status.Errorf(codes.Internal, "internal error")
+		}
+	}
+
+	return service.ContextWithPrincipal(ctx, principal), nil
+}
+
+func (p methodPolicy) allowsPersonalAccessToken(scopes []string) bool {
+	if p.scope == "" {
+		return false
+	}
+	return p.scope == scopeAny || slices.Contains(scopes, p.scope)
+}
+
+// проверенный интерцептором юзверь; для ручек, куда сервисные токены не пускаем
+func currentUser(ctx context.Context) (*pb.User, error) {
+	principal, ok := service.PrincipalFromContext(ctx)
+	if !ok || principal.User == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	return principal.User, nil
+}
@@ -0,0 +1,102 @@
+package gapi
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/service"
+)
+
+// описания scopes для экрана согласия
+var scopeDescriptions = map[string]string{
+	service.ScopeOpenID:  "Ваш идентификатор",
+	service.ScopeProfile: "Имя",
+	service.ScopeEmail:   "Адрес электронной почты",
+}
+
+var consentTemplate = template.Must(template.New("consent").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>Доступ к аккаунту</title>
+</head>
+<body>
+	<h1>{{.ClientName}} запрашивает доступ</h1>
+	<p>Вы вошли как <b>{{.Email}}</b>. Приложение получит:</p>
+	<ul>
+		{{range .Scopes}}<li>{{.}}</li>{{end}}
+	</ul>
+	<form method="post" action="/oauth/authorize">
+		<input type="hidden" name="response_type" value="{{.Request.ResponseType}}">
+		<input type="hidden" name="client_id" value="{{.Request.ClientID}}">
+		<input type="hidden" name="redirect_uri" value="{{.Request.RedirectURI}}">
+		<input type="hidden" name="scope" value="{{.Request.Scope}}">
+		<input type="hidden" name="state" value="{{.Request.State}}">
+		<input type="hidden" name="code_challenge" value="{{.Request.CodeChallenge}}">
+		<input type="hidden" name="code_challenge_method" value="{{.Request.CodeChallengeMethod}}">
+		<input type="hidden" name="nonce" value="{{.Request.Nonce}}">
+		<input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+		<button type="submit" name="decision" value="approve">Разрешить</button>
+		<button type="submit" name="decision" value="deny">Отказать</button>
+	</form>
+</body>
+</html>
+`))
+
+var oauthErrorTemplate = template.Must(template.New("oauth_error").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+	<meta charset="utf-8">
+	<title>Ошибка авторизации</title>
+</head>
+<body>
+	<h1>Не удалось выполнить вход</h1>
+	<p>{{.Error}}{{if .ErrorDescription}}: {{.ErrorDescription}}{{end}}</p>
+</body>
+</html>
+`))
+
+type consentPage struct {
+	ClientName string
+	Email      string
+	Scopes     []string
+	Request    *domain.AuthorizeRequest
+	CSRFToken  string
+}
+
+func renderConsent(w http.ResponseWriter, page consentPage) {
+	renderHTML(w, http.StatusOK, consentTemplate, page)
+}
+
+func renderOAuthError(w http.ResponseWriter, statusCode int, body oauthError) {
+	renderHTML(w, statusCode, oauthErrorTemplate, body)
+}
+
+func renderHTML(w http.ResponseWriter, statusCode int, tmpl *template.Template, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	// страницу с кнопкой "Разрешить" нельзя встраивать во фрейм (clickjacking)
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "frame-ancestors 'none'")
+	w.WriteHeader(statusCode)
+
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("failed to render %s page: %v\n", tmpl.Name(), err)
+	}
+}
+
+func describeScopes(scope string) []string {
+	var descriptions []string
+	for _, s := range strings.Fields(scope) {
+		if d, ok := scopeDescriptions[s]; ok {
+			descriptions = append(descriptions, d)
+			continue
+		}
+		descriptions = append(descriptions, s)
+	}
+	return descriptions
+}
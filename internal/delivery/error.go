@@ -1,9 +1,12 @@
 package gapi
 
 import (
+	pbv2 "github.com/Iowel/app-auth-service/pkg/pb/v2"
+
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
 )
 
 func fieldViolation(field string, err error) *errdetails.BadRequest_FieldViolation {
@@ -17,10 +20,30 @@ func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) er
 	badRequest := &errdetails.BadRequest{FieldViolations: violations}
 
 	statusInvalid := status.New(codes.InvalidArgument, "invalid parameters")
-	
+
 	statusDetails, err := statusInvalid.WithDetails(badRequest)
 	if err != nil {
 		return statusInvalid.Err()
 	}
 	return statusDetails.Err()
 }
+
+// домен в ErrorInfo: по нему клиент понимает, что reason из нашего списка pbv2.ErrorReason
+const errorInfoDomain = "app-auth-service"
+
+// статус с машиночитаемой причиной; extra - дополнительные детали, например RetryInfo
+func reasonError(code codes.Code, reason pbv2.ErrorReason, message string, metadata map[string]string, extra ...protoadapt.MessageV1) error {
+	errorInfo := &errdetails.ErrorInfo{
+		Reason:   reason.String(),
+		Domain:   errorInfoDomain,
+		Metadata: metadata,
+	}
+
+	st := status.New(code, message)
+
+	statusDetails, err := st.WithDetails(append([]protoadapt.MessageV1{errorInfo}, extra...)...)
+	if err != nil {
+		return st.Err()
+	}
+	return statusDetails.Err()
+}
@@ -0,0 +1,60 @@
+package gapi
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Iowel/app-auth-service/internal/service"
+)
+
+// заголовки, которые Traefik (authResponseHeaders) и nginx (auth_request_set) передают дальше в приложение
+const (
+	headerUserID    = "X-User-Id"
+	headerUserEmail = "X-User-Email"
+	headerUserRole  = "X-User-Role"
+	headerUserAppID = "X-User-App-Id"
+	// через запятую, из RBAC; X-User-Role - одна последняя назначенная роль, для старых приложений
+	headerUserRoles       = "X-User-Roles"
+	headerUserPermissions = "X-User-Permissions"
+)
+
+// ForwardAuth для Traefik и nginx auth_request: 200 с данными юзверя в заголовках, 401 или 403; тело прокси не читают
+func (server *Server) ForwardAuth(w http.ResponseWriter, r *http.Request) {
+	const op = "delivery.forward_auth.ForwardAuth"
+
+	w.Header().Set("Cache-Control", "no-store")
+
+	// отказы не логируем: прокси дергает нас на каждый запрос, HttpLogger их и так видит
+	principal, err := server.principalFromRequest(r)
+	if err != nil || principal.User == nil {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// скоупы personal access token приложения за прокси не видят, так что пускаем только полноценные токены
+	if principal.Scope == service.ScopePersonalAccess {
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	user := principal.User
+
+	// права берем из RBAC, а не из users.role: ролей у юзверя может быть несколько
+	perms, err := server.rbacService.UserPermissions(r.Context(), user.Id)
+	if err != nil {
+		log.Printf("Forward auth failed: path: %s, error: %v", op, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(headerUserID, strconv.FormatInt(user.Id, 10))
+	w.Header().Set(headerUserEmail, user.Email)
+	w.Header().Set(headerUserRole, user.Role)
+	w.Header().Set(headerUserAppID, strconv.FormatInt(user.AppId, 10))
+	w.Header().Set(headerUserRoles, strings.Join(perms.Roles, ","))
+	w.Header().Set(headerUserPermissions, strings.Join(perms.Permissions, ","))
+	w.WriteHeader(http.StatusOK)
+}
@@ -5,13 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"time"
 
 	"github.com/Iowel/app-auth-service/internal/domain"
 	"github.com/Iowel/app-auth-service/internal/pkg/worker"
+	"github.com/Iowel/app-auth-service/internal/service"
 	pb "github.com/Iowel/app-auth-service/pkg/pb"
 
 	"github.com/hibiken/asynq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -19,26 +22,11 @@ import (
 func (h *Server) RegisterUser(ctx context.Context, req *pb.RegisterUserRequest) (*pb.RegisterResponsePayload, error) {
 	const op = "delivery.handlers.RegisterUser"
 
-	params := domain.CreateUserTxParams{
-		User: &pb.User{Email: req.Email, Password: req.Password, Name: req.Name},
-
-		AfterCreate: func(user *pb.User) error {
-			taskPayload := &worker.PayloadSendVerifyEmail{
-				Name: user.Name,
-			}
-
-			opts := []asynq.Option{
-				asynq.MaxRetry(10),
-				asynq.ProcessIn(5 * time.Second),
-				asynq.Queue(worker.QueueCritical),
-			}
-
-			return h.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
-		},
-	}
-
-	user, err := h.authService.RegisterTx(ctx, params)
+	user, err := h.authService.RegisterTx(ctx, h.registerUserParams(ctx, req))
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAppID) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown or missing app_id")
+		}
 		if errors.Is(err, domain.ErrUserExists) {
 			return &pb.RegisterResponsePayload{
 				Error:   true,
@@ -52,21 +40,7 @@ func (h *Server) RegisterUser(ctx context.Context, req *pb.RegisterUserRequest)
 		}, nil
 	}
 
-	log.Printf("user %v\n", user)
-
-	// Создание профиля
-	// TODO: ВЫНЕСТИ ОТСЮДА ЕТОТ УЖАС
-	profile := &domain.Profile{
-		UserID:    int(user.User.Id),
-		Avatar:    "static/fox-icon.png",
-		About:     "",
-		Friends:   []int{},
-		Wallet:    0,
-		Status:    "Серебренный",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	err = h.authService.CreateProfile(profile)
+	err = h.createDefaultProfile(user.User.Id)
 	if err != nil {
 		return &pb.RegisterResponsePayload{
 			Error:   true,
@@ -81,35 +55,217 @@ func (h *Server) RegisterUser(ctx context.Context, req *pb.RegisterUserRequest)
 	}, nil
 }
 
+// общие для v1 и v2: письмо с подтверждением уходит в очередь в той же транзакции, что и создание юзверя
+func (h *Server) registerUserParams(ctx context.Context, req *pb.RegisterUserRequest) domain.CreateUserTxParams {
+	return domain.CreateUserTxParams{
+		User:     &pb.User{Email: req.Email, Name: req.Name, AppId: req.AppId},
+		Password: req.Password,
+
+		AfterCreate: func(user *pb.User) error {
+			taskPayload := &worker.PayloadSendVerifyEmail{
+				Name:   user.Name,
+				UserID: user.Id,
+			}
+
+			opts := []asynq.Option{
+				asynq.MaxRetry(10),
+				asynq.ProcessIn(5 * time.Second),
+				asynq.Queue(worker.QueueCritical),
+			}
+
+			return h.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
+		},
+	}
+}
+
+// Создание профиля
+// TODO: ВЫНЕСТИ ОТСЮДА ЕТОТ УЖАС
+func (h *Server) createDefaultProfile(userID int64) error {
+	profile := &domain.Profile{
+		UserID:    int(userID),
+		Avatar:    "static/fox-icon.png",
+		About:     "",
+		Friends:   []int{},
+		Wallet:    0,
+		Status:    "Серебренный",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	return h.authService.CreateProfile(profile)
+}
+
 func (h *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.LoginResponsePayload, error) {
-	token, err := h.authService.Login(req.Email, req.Password)
+	mtdt := h.extractMetadata(ctx)
+
+	result, err := h.authService.Login(ctx, domain.LoginParams{
+		AppID:     req.AppId,
+		Email:     req.Email,
+		Password:  req.Password,
+		UserAgent: mtdt.UserAgent,
+		ClientIP:  mtdt.ClientIP,
+	})
 	if err != nil {
+		var throttled *domain.LoginThrottledError
+
 		switch {
 
+		case errors.Is(err, domain.ErrInvalidAppID):
+			return nil, status.Errorf(codes.InvalidArgument, "unknown or missing app_id")
+
 		case errors.Is(err, domain.ErrWrongCredentials), errors.Is(err, domain.ErrUserNotFound):
 			return &pb.LoginResponsePayload{
-				Error:   true,
-				Message: "неверный email или пароль",
+				Error:     true,
+				Message:   "неверный email или пароль",
+				ErrorCode: pb.LoginErrorCode_LOGIN_ERROR_CODE_WRONG_CREDENTIALS,
 			}, nil
 
 		case errors.Is(err, domain.Isemailverified):
 			return &pb.LoginResponsePayload{
-				Error:   true,
-				Message: "Email не подтвержден",
+				Error:     true,
+				Message:   "Email не подтвержден",
+				ErrorCode: pb.LoginErrorCode_LOGIN_ERROR_CODE_EMAIL_NOT_VERIFIED,
 			}, nil
 
+		case errors.As(err, &throttled):
+			resp := &pb.LoginResponsePayload{
+				Error:             true,
+				Message:           "слишком много попыток входа, попробуйте позже",
+				ErrorCode:         pb.LoginErrorCode_LOGIN_ERROR_CODE_TOO_MANY_ATTEMPTS,
+				RetryAfterSeconds: int64(math.Ceil(throttled.RetryAfter.Seconds())),
+			}
+			if throttled.Locked {
+				resp.Message = "вход временно заблокирован после неудачных попыток"
+				resp.ErrorCode = pb.LoginErrorCode_LOGIN_ERROR_CODE_ACCOUNT_LOCKED
+			}
+			return resp, nil
+
 		default:
 			return nil, status.Errorf(codes.Internal, "ошибка входа: %v", err)
 		}
 	}
 
+	if result.ChallengeToken != "" {
+		return &pb.LoginResponsePayload{
+			Error:             false,
+			Message:           "требуется код двухфакторной аутентификации",
+			TwoFactorRequired: true,
+			ChallengeToken:    result.ChallengeToken,
+		}, nil
+	}
+
 	return &pb.LoginResponsePayload{
-		Error:   false,
-		Message: "success",
-		Token:   token,
+		Error:        false,
+		Message:      "success",
+		Token:        result.Tokens.AccessToken,
+		RefreshToken: result.Tokens.RefreshToken,
 	}, nil
 }
 
+func (h *Server) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	const op = "delivery.RefreshToken"
+
+	tokens, err := h.authService.RefreshToken(ctx, req.GetRefreshToken(), "")
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidRefreshToken), errors.Is(err, domain.ErrRefreshTokenReused):
+			return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token")
+		default:
+			log.Printf("Token refresh failed: path: %s, error: %v", op, err)
+			return nil, status.Errorf(codes.Internal, "failed to refresh token")
+		}
+	}
+
+	resp := &pb.RefreshTokenResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}
+
+	return resp, nil
+}
+
+func (h *Server) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	const op = "delivery.Logout"
+
+	err := h.authService.Logout(ctx)
+	if err != nil {
+		if errors.Is(err, domain.ErrUnauthenticated) {
+			return nil, status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+		}
+		log.Printf("Logout failed: path: %s, error: %v", op, err)
+		return nil, status.Errorf(codes.Internal, "failed to logout")
+	}
+
+	return &pb.LogoutResponse{}, nil
+}
+
+func (h *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	const op = "delivery.UpdateUser"
+
+	authUser, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := domain.UpdateUserTxParams{
+		UpdateUserParams: domain.UpdateUserParams{
+			ID:       authUser.Id,
+			Name:     req.Name,
+			Email:    req.Email,
+			Password: req.Password,
+		},
+		CurrentPassword: req.GetCurrentPassword(),
+
+		// при смене email отправляем письмо для повторного подтверждения
+		AfterEmailChange: func(user *pb.User) error {
+			taskPayload := &worker.PayloadSendVerifyEmail{
+				Name:   user.Name,
+				UserID: user.Id,
+			}
+
+			opts := []asynq.Option{
+				asynq.MaxRetry(10),
+				asynq.ProcessIn(5 * time.Second),
+				asynq.Queue(worker.QueueCritical),
+			}
+
+			return h.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
+		},
+	}
+
+	result, err := h.authService.UpdateUserTx(ctx, params)
+	if err != nil {
+		var throttled *domain.LoginThrottledError
+
+		switch {
+		case errors.Is(err, domain.ErrCurrentPasswordRequired):
+			return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("current_password", err)})
+		case errors.Is(err, domain.ErrWrongCredentials):
+			return nil, status.Errorf(codes.PermissionDenied, "current password is incorrect")
+		case errors.As(err, &throttled):
+			return nil, status.Errorf(codes.ResourceExhausted, "too many attempts, retry in %d seconds", int64(math.Ceil(throttled.RetryAfter.Seconds())))
+		case errors.Is(err, domain.ErrUserExists):
+			return nil, status.Errorf(codes.AlreadyExists, "email already in use")
+		case errors.Is(err, domain.ErrUserNotFound):
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		default:
+			log.Printf("User update failed: path: %s, error: %v", op, err)
+			return nil, status.Errorf(codes.Internal, "failed to update user")
+		}
+	}
+
+	user := result.User
+
+	mtdt := h.extractMetadata(ctx)
+
+	resp := &pb.UpdateUserResponse{
+		User:      user,
+		UserAgent: mtdt.UserAgent,
+		ClientIp:  mtdt.ClientIP,
+	}
+
+	return resp, nil
+}
+
 func (h *Server) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
 	const op = "delivery.VerifyEmail"
 
@@ -131,26 +287,40 @@ func (h *Server) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*
 func (h *Server) VerifyToken(ctx context.Context, req *pb.VerifyTokenRequest) (*pb.VerifyTokenResponse, error) {
 	const op = "delivery.VerifyToken"
 
-	user, err := h.authService.AuthorizeUser(ctx)
-	if err != nil {
+	info, ok := service.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to verify token")
+	}
+
+	// токен сервиса (client_credentials): юзверя и приложения у него нет
+	if info.Service != nil {
 		return &pb.VerifyTokenResponse{
-			Error:   true,
-			Message: "failed to verify token",
-		}, err
+			Error:    false,
+			Message:  fmt.Sprintf("authenticated service %s", info.Service.Name),
+			Scopes:   info.Scopes,
+			ClientId: info.Service.ClientID,
+		}, nil
+	}
+	user := info.User
+
+	// токен одного приложения не должен открывать доступ к другому; старые клиенты app_id не передают
+	if user.AppId != domain.AppIDOrDefault(req.GetAppId()) {
+		return nil, status.Errorf(codes.PermissionDenied, "token was issued for another app")
 	}
 
 	resp := &pb.VerifyTokenResponse{
 		Error:   false,
 		Message: fmt.Sprintf("authenticated user %s", user.Email),
+		Scopes:  info.Scopes,
 	}
 
 	return resp, nil
 }
 
 func (h *Server) VerifyRole(ctx context.Context, req *pb.VerifyRoleRequest) (*pb.VerifyRoleResponse, error) {
-	const op = "delivery.VerifyToken"
+	const op = "delivery.VerifyRole"
 
-	user, err := h.authService.AuthorizeUser(ctx)
+	user, err := currentUser(ctx)
 	if err != nil {
 		return &pb.VerifyRoleResponse{
 			Error:   true,
@@ -158,17 +328,36 @@ func (h *Server) VerifyRole(ctx context.Context, req *pb.VerifyRoleRequest) (*pb
 		}, nil
 	}
 
-	if user.Role != "admin" && user.Role != "moderator" {
+	perms, err := h.rbacService.UserPermissions(ctx, user.Id)
+	if err != nil {
+		log.Printf("Failed to load permissions: path: %s, error: %v", op, err)
+		return nil, status.Errorf(codes.Internal, "failed to load permissions")
+	}
+
+	if !roleAllowed(perms, req) {
 		return &pb.VerifyRoleResponse{
-			Error:   true,
-			Message: "failed to verify role",
+			Error:       true,
+			Message:     "failed to verify role",
+			Roles:       perms.Roles,
+			Permissions: perms.Permissions,
 		}, nil
 	}
 
 	resp := &pb.VerifyRoleResponse{
-		Error:   false,
-		Message: fmt.Sprintf("authenticated user with role %s", user.Role),
+		Error:       false,
+		Message:     fmt.Sprintf("authenticated user with roles %v", perms.Roles),
+		Roles:       perms.Roles,
+		Permissions: perms.Permissions,
 	}
 
 	return resp, nil
 }
+
+func roleAllowed(perms *domain.UserPermissions, req *pb.VerifyRoleRequest) bool {
+	if req.GetRole() == "" && req.GetPermission() == "" {
+		// старые клиенты ничего не передают и ждут проверку на admin или moderator
+		return perms.HasRole("admin") || perms.HasRole("moderator")
+	}
+	return (req.GetRole() == "" || perms.HasRole(req.GetRole())) &&
+		(req.GetPermission() == "" || perms.HasPermission(req.GetPermission()))
+}
@@ -0,0 +1,169 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/service"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+	pbv2 "github.com/Iowel/app-auth-service/pkg/pb/v2"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var _ pbv2.AuthServiceServer = &ServerV2{}
+
+// v2 поверх тех же сервисов, что и v1; отдельный тип, потому что имена методов совпадают
+type ServerV2 struct {
+	pbv2.UnimplementedAuthServiceServer
+	h *Server
+}
+
+func NewServerV2(server *Server) *ServerV2 {
+	return &ServerV2{
+		h: server,
+	}
+}
+
+func (s *ServerV2) RegisterUser(ctx context.Context, req *pb.RegisterUserRequest) (*pbv2.RegisterUserResponse, error) {
+	const op = "delivery.handler_v2.RegisterUser"
+
+	user, err := s.h.authService.RegisterTx(ctx, s.h.registerUserParams(ctx, req))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidAppID):
+			return nil, reasonError(codes.InvalidArgument, pbv2.ErrorReason_INVALID_APP_ID, "unknown or missing app_id", nil)
+		case errors.Is(err, domain.ErrUserExists):
+			return nil, reasonError(codes.AlreadyExists, pbv2.ErrorReason_USER_EXISTS, "user already exists", nil)
+		default:
+			log.Printf("User creation failed: path: %s, error: %v", op, err)
+			return nil, status.Errorf(codes.Internal, "failed to register user")
+		}
+	}
+
+	err = s.h.createDefaultProfile(user.User.Id)
+	if err != nil {
+		log.Printf("Profile creation failed: path: %s, error: %v", op, err)
+		return nil, status.Errorf(codes.Internal, "failed to create profile")
+	}
+
+	return &pbv2.RegisterUserResponse{
+		UserId: user.User.Id,
+	}, nil
+}
+
+func (s *ServerV2) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pbv2.LoginUserResponse, error) {
+	const op = "delivery.handler_v2.LoginUser"
+
+	mtdt := s.h.extractMetadata(ctx)
+
+	result, err := s.h.authService.Login(ctx, domain.LoginParams{
+		AppID:     req.AppId,
+		Email:     req.Email,
+		Password:  req.Password,
+		UserAgent: mtdt.UserAgent,
+		ClientIP:  mtdt.ClientIP,
+	})
+	if err != nil {
+		var throttled *domain.LoginThrottledError
+
+		switch {
+		case errors.Is(err, domain.ErrInvalidAppID):
+			return nil, reasonError(codes.InvalidArgument, pbv2.ErrorReason_INVALID_APP_ID, "unknown or missing app_id", nil)
+
+		// не различаем, чтобы по ответу нельзя было перебирать email
+		case errors.Is(err, domain.ErrWrongCredentials), errors.Is(err, domain.ErrUserNotFound):
+			return nil, reasonError(codes.Unauthenticated, pbv2.ErrorReason_WRONG_CREDENTIALS, "wrong email or password", nil)
+
+		case errors.Is(err, domain.Isemailverified):
+			return nil, reasonError(codes.FailedPrecondition, pbv2.ErrorReason_EMAIL_NOT_VERIFIED, "email is not verified", nil)
+
+		// RetryInfo gateway превращает в Retry-After, как и для rate limit
+		case errors.As(err, &throttled):
+			retryInfo := &errdetails.RetryInfo{RetryDelay: durationpb.New(throttled.RetryAfter)}
+			if throttled.Locked {
+				return nil, reasonError(codes.ResourceExhausted, pbv2.ErrorReason_ACCOUNT_LOCKED, "login is temporarily locked after failed attempts", nil, retryInfo)
+			}
+			return nil, reasonError(codes.ResourceExhausted, pbv2.ErrorReason_TOO_MANY_ATTEMPTS, "too many login attempts", nil, retryInfo)
+
+		default:
+			log.Printf("Login failed: path: %s, error: %v", op, err)
+			return nil, status.Errorf(codes.Internal, "failed to login")
+		}
+	}
+
+	if result.ChallengeToken != "" {
+		return &pbv2.LoginUserResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.ChallengeToken,
+		}, nil
+	}
+
+	return &pbv2.LoginUserResponse{
+		Token:        result.Tokens.AccessToken,
+		RefreshToken: result.Tokens.RefreshToken,
+	}, nil
+}
+
+// токен уже проверил интерцептор; тут только привязка к приложению
+func (s *ServerV2) VerifyToken(ctx context.Context, req *pb.VerifyTokenRequest) (*pbv2.VerifyTokenResponse, error) {
+	info, ok := service.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to verify token")
+	}
+
+	if info.Service != nil {
+		return &pbv2.VerifyTokenResponse{
+			Scopes:   info.Scopes,
+			ClientId: info.Service.ClientID,
+		}, nil
+	}
+	user := info.User
+
+	if user.AppId != domain.AppIDOrDefault(req.GetAppId()) {
+		return nil, reasonError(codes.PermissionDenied, pbv2.ErrorReason_TOKEN_APP_MISMATCH, "token was issued for another app", nil)
+	}
+
+	return &pbv2.VerifyTokenResponse{
+		UserId: user.Id,
+		Email:  user.Email,
+		AppId:  user.AppId,
+		Scopes: info.Scopes,
+	}, nil
+}
+
+func (s *ServerV2) VerifyRole(ctx context.Context, req *pb.VerifyRoleRequest) (*pbv2.VerifyRoleResponse, error) {
+	const op = "delivery.handler_v2.VerifyRole"
+
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	perms, err := s.h.rbacService.UserPermissions(ctx, user.Id)
+	if err != nil {
+		log.Printf("Failed to load permissions: path: %s, error: %v", op, err)
+		return nil, status.Errorf(codes.Internal, "failed to load permissions")
+	}
+
+	if !roleAllowed(perms, req) {
+		metadata := map[string]string{}
+		if req.GetRole() != "" {
+			metadata["role"] = req.GetRole()
+		}
+		if req.GetPermission() != "" {
+			metadata["permission"] = req.GetPermission()
+		}
+		return nil, reasonError(codes.PermissionDenied, pbv2.ErrorReason_INSUFFICIENT_ROLE, "failed to verify role", metadata)
+	}
+
+	return &pbv2.VerifyRoleResponse{
+		Roles:       perms.Roles,
+		Permissions: perms.Permissions,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package gapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// отдаем публичные ключи, по которым другие сервисы сами проверяют JWT
+func (server *Server) JWKS(w http.ResponseWriter, r *http.Request) {
+	jwks := server.keyService.JWKS()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	if err := json.NewEncoder(w).Encode(jwks); err != nil {
+		http.Error(w, "failed to encode jwks", http.StatusInternalServerError)
+	}
+}
+
+func (h *Server) RotateSigningKey(ctx context.Context, req *pb.RotateSigningKeyRequest) (*pb.RotateSigningKeyResponse, error) {
+	const op = "delivery.RotateSigningKey"
+
+	key, err := h.keyService.Rotate(ctx)
+	if err != nil {
+		if errors.Is(err, domain.ErrJWTDisabled) {
+			return nil, status.Errorf(codes.FailedPrecondition, "jwt tokens are disabled")
+		}
+		log.Printf("Key rotation failed: path: %s, error: %v", op, err)
+		return nil, status.Errorf(codes.Internal, "failed to rotate signing key")
+	}
+
+	resp := &pb.RotateSigningKeyResponse{
+		Kid:       key.Kid,
+		CreatedAt: timestamppb.New(key.CreatedAt),
+	}
+	if key.ActivatesAt != nil {
+		resp.ActivatesAt = timestamppb.New(*key.ActivatesAt)
+	}
+
+	return resp, nil
+}
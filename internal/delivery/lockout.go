@@ -0,0 +1,29 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// снять блокировку после неудачных логинов до истечения ее срока
+func (h *Server) UnlockAccount(ctx context.Context, req *pb.UnlockAccountRequest) (*pb.UnlockAccountResponse, error) {
+	const op = "delivery.UnlockAccount"
+
+	err := h.authService.UnlockAccount(ctx, domain.AppIDOrDefault(req.GetAppId()), req.GetEmail())
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		log.Printf("Account unlock failed: path: %s, error: %v", op, err)
+		return nil, status.Errorf(codes.Internal, "failed to unlock account")
+	}
+
+	return &pb.UnlockAccountResponse{}, nil
+}
@@ -0,0 +1,83 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/pkg/worker"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"github.com/hibiken/asynq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *Server) RequestMagicLink(ctx context.Context, req *pb.RequestMagicLinkRequest) (*pb.RequestMagicLinkResponse, error) {
+	const op = "delivery.RequestMagicLink"
+
+	mtdt := h.extractMetadata(ctx)
+
+	err := h.authService.RequestMagicLink(ctx, domain.RequestMagicLinkParams{
+		AppID: req.GetAppId(),
+		Email: req.GetEmail(),
+
+		AfterFind: func(user *pb.User) error {
+			taskPayload := &worker.PayloadSendMagicLink{
+				UserID:    user.Id,
+				UserAgent: mtdt.UserAgent,
+			}
+
+			opts := []asynq.Option{
+				asynq.MaxRetry(5),
+				asynq.Queue(worker.QueueCritical),
+			}
+
+			// ошибку не возвращаем: ответ для существующего и несуществующего email должен быть одинаковым
+			err := h.taskDistributor.DistributeTaskSendMagicLink(ctx, taskPayload, opts...)
+			if err != nil {
+				log.Printf("RequestMagicLink failed to enqueue task: path: %s, error: %v", op, err)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		log.Printf("RequestMagicLink failed: path: %s, error: %v", op, err)
+		return nil, status.Errorf(codes.Internal, "internal error")
+	}
+
+	return &pb.RequestMagicLinkResponse{}, nil
+}
+
+func (h *Server) ConsumeMagicLink(ctx context.Context, req *pb.ConsumeMagicLinkRequest) (*pb.ConsumeMagicLinkResponse, error) {
+	const op = "delivery.ConsumeMagicLink"
+
+	mtdt := h.extractMetadata(ctx)
+
+	result, err := h.authService.ConsumeMagicLink(ctx, domain.ConsumeMagicLinkParams{
+		LinkID:    req.GetLinkId(),
+		Code:      req.GetCode(),
+		UserAgent: mtdt.UserAgent,
+		ClientIP:  mtdt.ClientIP,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMagicLink) {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid or expired login link")
+		}
+		log.Printf("ConsumeMagicLink failed: path: %s, error: %v", op, err)
+		return nil, status.Errorf(codes.Internal, "internal error")
+	}
+
+	if result.ChallengeToken != "" {
+		return &pb.ConsumeMagicLinkResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.ChallengeToken,
+		}, nil
+	}
+
+	return &pb.ConsumeMagicLinkResponse{
+		AccessToken:  result.Tokens.AccessToken,
+		RefreshToken: result.Tokens.RefreshToken,
+	}, nil
+}
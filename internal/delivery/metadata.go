@@ -2,6 +2,9 @@ package gapi
 
 import (
 	"context"
+	"net"
+	"net/http"
+	"strings"
 
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
@@ -21,19 +24,27 @@ type Metadata struct {
 }
 
 func (server *Server) extractMetadata(ctx context.Context) *Metadata {
+	return metadataFromContext(ctx)
+}
+
+// отдельно от Server, чтобы пользоваться и в интерцепторах
+func metadataFromContext(ctx context.Context) *Metadata {
 	mtdt := &Metadata{}
 
+	var forwardedFor string
+
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		if userAgents := md.Get(grpcGatewayUserAgentHeader); len(userAgents) > 0 {
+		// gateway ходит к нам gRPC-клиентом со своим user-agent, браузерный приходит отдельным заголовком
+		if userAgents := md.Get(userAgentHeader); len(userAgents) > 0 {
 			mtdt.UserAgent = userAgents[0]
 		}
 
-		if userAgents := md.Get(userAgentHeader); len(userAgents) > 0 {
+		if userAgents := md.Get(grpcGatewayUserAgentHeader); len(userAgents) > 0 {
 			mtdt.UserAgent = userAgents[0]
 		}
 
 		if clientIPs := md.Get(xForwardedForHeader); len(clientIPs) > 0 {
-			mtdt.ClientIP = clientIPs[0]
+			forwardedFor = clientIPs[len(clientIPs)-1]
 		}
 	}
 
@@ -41,5 +52,28 @@ func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 		mtdt.ClientIP = p.Addr.String()
 	}
 
+	// x-forwarded-for верим только от нашего gateway на том же хосте, остальные могут его подделать.
+	// gateway дописывает адрес клиента в конец списка
+	if forwardedFor != "" && (mtdt.ClientIP == "" || isLoopback(mtdt.ClientIP)) {
+		addrs := strings.Split(forwardedFor, ",")
+		mtdt.ClientIP = strings.TrimSpace(addrs[len(addrs)-1])
+	}
+
 	return mtdt
 }
+
+func isLoopback(addr string) bool {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip := net.ParseIP(addr)
+	return ip != nil && ip.IsLoopback()
+}
+
+// то же самое для обычных HTTP ручек gateway, которые не проходят через gRPC
+func httpMetadata(r *http.Request) *Metadata {
+	return &Metadata{
+		UserAgent: r.UserAgent(),
+		ClientIP:  r.RemoteAddr,
+	}
+}
@@ -0,0 +1,355 @@
+package gapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/service"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// OAuth 2.0 ручки поверх gateway, отвечают в формате RFC, а не нашими proto-сообщениями
+
+type oauthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// cookie, в которой фронтенд хранит access токен - браузер не пришлет заголовок Authorization при редиректе
+const accessTokenCookie = "access_token"
+
+// authorization endpoint: GET показывает экран согласия, POST принимает решение юзверя
+func (server *Server) OAuthAuthorize(w http.ResponseWriter, r *http.Request) {
+	const op = "delivery.oauth.OAuthAuthorize"
+
+	if err := r.ParseForm(); err != nil {
+		renderOAuthError(w, http.StatusBadRequest, oauthError{Error: "invalid_request", ErrorDescription: "malformed request"})
+		return
+	}
+
+	req := &domain.AuthorizeRequest{
+		ResponseType:        r.Form.Get("response_type"),
+		ClientID:            r.Form.Get("client_id"),
+		RedirectURI:         r.Form.Get("redirect_uri"),
+		Scope:               r.Form.Get("scope"),
+		State:               r.Form.Get("state"),
+		CodeChallenge:       r.Form.Get("code_challenge"),
+		CodeChallengeMethod: r.Form.Get("code_challenge_method"),
+		Nonce:               r.Form.Get("nonce"),
+	}
+
+	client, err := server.oauthService.ValidateAuthorizeRequest(r.Context(), req)
+	if err != nil {
+		switch {
+		// адрес возврата не проверен - редиректить на него нельзя, показываем ошибку сами
+		case errors.Is(err, domain.ErrInvalidClient), errors.Is(err, domain.ErrInvalidRedirectURI):
+			renderOAuthError(w, http.StatusBadRequest, oauthError{Error: "invalid_request", ErrorDescription: err.Error()})
+		default:
+			redirectWithOAuthError(w, r, req, oauthErrorFromDomain(op, err))
+		}
+		return
+	}
+
+	// согласие выдает приложению полноценные токены - personal access token для этого не годится
+	principal, err := server.principalFromRequest(r)
+	if err != nil || principal.User == nil || principal.Scope == service.ScopePersonalAccess {
+		server.redirectToLogin(w, r, req)
+		return
+	}
+	user := principal.User
+
+	if r.Method == http.MethodPost {
+		expected := server.consentToken(user.Id, req)
+		if !hmac.Equal([]byte(r.PostForm.Get("csrf_token")), []byte(expected)) {
+			renderOAuthError(w, http.StatusForbidden, oauthError{Error: "access_denied", ErrorDescription: "invalid csrf token"})
+			return
+		}
+
+		if r.PostForm.Get("decision") != "approve" {
+			redirectWithOAuthError(w, r, req, oauthError{Error: "access_denied", ErrorDescription: "user denied the request"})
+			return
+		}
+	} else {
+		needsConsent, err := server.oauthService.NeedsConsent(r.Context(), user, client, req.Scope)
+		if err != nil {
+			log.Printf("consent check failed, path: %s, error: %v\n", op, err)
+			redirectWithOAuthError(w, r, req, oauthError{Error: "server_error"})
+			return
+		}
+
+		if needsConsent {
+			renderConsent(w, consentPage{
+				ClientName: client.Name,
+				Email:      user.Email,
+				Scopes:     describeScopes(req.Scope),
+				Request:    req,
+				CSRFToken:  server.consentToken(user.Id, req),
+			})
+			return
+		}
+	}
+
+	code, err := server.oauthService.Authorize(r.Context(), user, client, req)
+	if err != nil {
+		log.Printf("authorization failed, path: %s, error: %v\n", op, err)
+		redirectWithOAuthError(w, r, req, oauthError{Error: "server_error"})
+		return
+	}
+
+	params := url.Values{"code": {code}}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	redirectWithParams(w, r, req.RedirectURI, params)
+}
+
+// token endpoint: обмен кода (authorization_code + PKCE), обновление токенов (refresh_token)
+// и токены для сервисов (client_credentials)
+func (server *Server) Token(w http.ResponseWriter, r *http.Request) {
+	const op = "delivery.oauth.Token"
+
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, oauthError{Error: "invalid_request", ErrorDescription: "malformed form body"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+
+	client, err := server.oauthService.AuthenticateClient(r.Context(), clientID, clientSecret)
+	if err != nil {
+		if !errors.Is(err, domain.ErrInvalidClient) {
+			log.Printf("client authentication failed, path: %s, error: %v\n", op, err)
+			writeJSON(w, http.StatusInternalServerError, oauthError{Error: "server_error"})
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="token"`)
+		writeJSON(w, http.StatusUnauthorized, oauthError{Error: "invalid_client"})
+		return
+	}
+
+	var resp *domain.OAuthTokenResponse
+
+	switch r.PostForm.Get("grant_type") {
+	case service.GrantTypeAuthorizationCode:
+		mtdt := httpMetadata(r)
+		resp, err = server.oauthService.ExchangeCode(r.Context(), client, domain.CodeExchangeParams{
+			Code:         r.PostForm.Get("code"),
+			RedirectURI:  r.PostForm.Get("redirect_uri"),
+			CodeVerifier: r.PostForm.Get("code_verifier"),
+			UserAgent:    mtdt.UserAgent,
+			ClientIP:     mtdt.ClientIP,
+		})
+	case service.GrantTypeRefreshToken:
+		resp, err = server.oauthService.RefreshToken(r.Context(), client, r.PostForm.Get("refresh_token"))
+	case service.GrantTypeClientCredentials:
+		resp, err = server.oauthService.ClientCredentials(r.Context(), client, r.PostForm.Get("scope"))
+	default:
+		err = domain.ErrUnsupportedGrantType
+	}
+
+	if err != nil {
+		body := oauthErrorFromDomain(op, err)
+		statusCode := http.StatusBadRequest
+		if body.Error == "server_error" {
+			statusCode = http.StatusInternalServerError
+		}
+		writeJSON(w, statusCode, body)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// юзверь из заголовка Authorization или из cookie
+func (server *Server) userFromRequest(r *http.Request) (*pb.User, error) {
+	principal, err := server.principalFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	if principal.User == nil {
+		return nil, errors.New("token does not belong to a user")
+	}
+	return principal.User, nil
+}
+
+func (server *Server) principalFromRequest(r *http.Request) (*domain.TokenInfo, error) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		cookie, err := r.Cookie(accessTokenCookie)
+		if err != nil {
+			return nil, err
+		}
+		authHeader = "Bearer " + cookie.Value
+	}
+
+	// AuthenticateToken читает токен из gRPC метаданных
+	ctx := metadata.NewIncomingContext(r.Context(), metadata.Pairs("authorization", authHeader))
+	return server.authService.AuthenticateToken(ctx)
+}
+
+// незалогиненного юзверя отправляем на страницу логина, после входа он вернется сюда же
+func (server *Server) redirectToLogin(w http.ResponseWriter, r *http.Request, req *domain.AuthorizeRequest) {
+	if server.cfg.Auth.LoginURL == "" {
+		renderOAuthError(w, http.StatusUnauthorized, oauthError{Error: "login_required"})
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	query := url.Values{
+		"response_type":         {req.ResponseType},
+		"client_id":             {req.ClientID},
+		"redirect_uri":          {req.RedirectURI},
+		"scope":                 {req.Scope},
+		"code_challenge":        {req.CodeChallenge},
+		"code_challenge_method": {req.CodeChallengeMethod},
+	}
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+	if req.Nonce != "" {
+		query.Set("nonce", req.Nonce)
+	}
+	returnTo := fmt.Sprintf("%s://%s%s?%s", scheme, r.Host, r.URL.Path, query.Encode())
+
+	redirectWithParams(w, r, server.cfg.Auth.LoginURL, url.Values{"return_to": {returnTo}})
+}
+
+// привязываем форму согласия к юзверю и параметрам запроса, чтобы ее нельзя было отправить с чужого сайта
+func (server *Server) consentToken(userID int64, req *domain.AuthorizeRequest) string {
+	mac := hmac.New(sha256.New, []byte(server.cfg.Auth.Secret))
+	fmt.Fprintf(mac, "%d\n%s\n%s\n%s\n%s\n%s\n%s", userID, req.ClientID, req.RedirectURI, req.Scope, req.State, req.CodeChallenge, req.Nonce)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func redirectWithOAuthError(w http.ResponseWriter, r *http.Request, req *domain.AuthorizeRequest, body oauthError) {
+	params := url.Values{"error": {body.Error}}
+	if body.ErrorDescription != "" {
+		params.Set("error_description", body.ErrorDescription)
+	}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	redirectWithParams(w, r, req.RedirectURI, params)
+}
+
+func redirectWithParams(w http.ResponseWriter, r *http.Request, target string, params url.Values) {
+	u, err := url.Parse(target)
+	if err != nil {
+		renderOAuthError(w, http.StatusInternalServerError, oauthError{Error: "server_error"})
+		return
+	}
+
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+// доменные ошибки в коды из RFC 6749
+func oauthErrorFromDomain(op string, err error) oauthError {
+	switch {
+	case errors.Is(err, domain.ErrInvalidClient):
+		return oauthError{Error: "invalid_client"}
+	case errors.Is(err, domain.ErrInvalidGrant):
+		return oauthError{Error: "invalid_grant"}
+	case errors.Is(err, domain.ErrInvalidScope):
+		return oauthError{Error: "invalid_scope", ErrorDescription: err.Error()}
+	case errors.Is(err, domain.ErrUnsupportedGrantType):
+		return oauthError{Error: "unsupported_grant_type"}
+	case errors.Is(err, domain.ErrUnauthorizedClient):
+		return oauthError{Error: "unauthorized_client"}
+	case errors.Is(err, domain.ErrUnsupportedResponseType):
+		return oauthError{Error: "unsupported_response_type"}
+	case errors.Is(err, domain.ErrInvalidOAuthRequest), errors.Is(err, domain.ErrInvalidRedirectURI):
+		return oauthError{Error: "invalid_request", ErrorDescription: err.Error()}
+	default:
+		log.Printf("oauth request failed, path: %s, error: %v\n", op, err)
+		return oauthError{Error: "server_error"}
+	}
+}
+
+// token introspection (RFC 7662) для API gateway и сторонних инструментов
+func (server *Server) Introspect(w http.ResponseWriter, r *http.Request) {
+	const op = "delivery.oauth.Introspect"
+
+	if !server.authenticateIntrospectionClient(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="introspect"`)
+		writeJSON(w, http.StatusUnauthorized, oauthError{Error: "invalid_client"})
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, oauthError{Error: "invalid_request", ErrorDescription: "malformed form body"})
+		return
+	}
+
+	result, err := server.authService.IntrospectToken(r.Context(), r.PostForm.Get("token"))
+	if err != nil {
+		log.Printf("token introspection failed, path: %s, error: %v\n", op, err)
+		writeJSON(w, http.StatusInternalServerError, oauthError{Error: "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// client_secret_basic или client_secret_post; клиенты из INTROSPECTION_CLIENTS или зарегистрированные машинные клиенты
+func (server *Server) authenticateIntrospectionClient(r *http.Request) bool {
+	const op = "delivery.oauth.authenticateIntrospectionClient"
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostFormValue("client_id")
+		clientSecret = r.PostFormValue("client_secret")
+	}
+
+	if clientID == "" || clientSecret == "" {
+		return false
+	}
+
+	if expected, ok := server.cfg.Auth.IntrospectionClients[clientID]; ok {
+		return subtle.ConstantTimeCompare([]byte(clientSecret), []byte(expected)) == 1
+	}
+
+	client, err := server.oauthService.AuthenticateClient(r.Context(), clientID, clientSecret)
+	if err != nil {
+		if !errors.Is(err, domain.ErrInvalidClient) {
+			log.Printf("client authentication failed, path: %s, error: %v\n", op, err)
+		}
+		return false
+	}
+
+	return client.IsService
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("failed to write json response: %v\n", err)
+	}
+}
@@ -0,0 +1,91 @@
+package gapi
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/pkg/jwtauth"
+	"github.com/Iowel/app-auth-service/internal/service"
+)
+
+// OpenID Connect Discovery 1.0, раздел 3
+type openIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksURI                           string   `json:"jwks_uri"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+func (server *Server) OpenIDConfiguration(w http.ResponseWriter, r *http.Request) {
+	issuer := strings.TrimSuffix(server.oauthService.Issuer(), "/")
+	if issuer == "" {
+		writeJSON(w, http.StatusNotFound, oauthError{Error: "not_found", ErrorDescription: "openid connect is disabled"})
+		return
+	}
+
+	config := openIDConfiguration{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/oauth/authorize",
+		TokenEndpoint:                     issuer + "/oauth/token",
+		UserinfoEndpoint:                  issuer + "/userinfo",
+		JwksURI:                           issuer + "/.well-known/jwks.json",
+		IntrospectionEndpoint:             issuer + "/oauth/introspect",
+		ScopesSupported:                   []string{service.ScopeOpenID, service.ScopeProfile, service.ScopeEmail},
+		ResponseTypesSupported:            []string{service.ResponseTypeCode},
+		GrantTypesSupported:               []string{service.GrantTypeAuthorizationCode, service.GrantTypeRefreshToken, service.GrantTypeClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{jwtauth.Algorithm},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{service.CodeChallengeMethodS256},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "nonce", "at_hash", "email", "email_verified", "name"},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+
+	if err := json.NewEncoder(w).Encode(config); err != nil {
+		http.Error(w, "failed to encode openid configuration", http.StatusInternalServerError)
+	}
+}
+
+// userinfo endpoint: claims о владельце access токена (RFC 6750 для ошибок)
+func (server *Server) UserInfo(w http.ResponseWriter, r *http.Request) {
+	const op = "delivery.oidc.UserInfo"
+
+	var accessToken string
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "bearer") {
+		accessToken = strings.TrimSpace(token)
+	}
+
+	info, err := server.oauthService.UserInfo(r.Context(), accessToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnauthenticated):
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeJSON(w, http.StatusUnauthorized, oauthError{Error: "invalid_token"})
+		case errors.Is(err, domain.ErrInsufficientScope):
+			w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="openid"`)
+			writeJSON(w, http.StatusForbidden, oauthError{Error: "insufficient_scope"})
+		default:
+			log.Printf("userinfo failed, path: %s, error: %v\n", op, err)
+			writeJSON(w, http.StatusInternalServerError, oauthError{Error: "server_error"})
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, info)
+}
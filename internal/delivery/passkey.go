@@ -0,0 +1,106 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *Server) BeginPasskeyRegistration(ctx context.Context, req *pb.BeginPasskeyRegistrationRequest) (*pb.BeginPasskeyRegistrationResponse, error) {
+	const op = "delivery.BeginPasskeyRegistration"
+
+	options, err := h.passkeyService.BeginPasskeyRegistration(ctx)
+	if err != nil {
+		return nil, passkeyError(op, err)
+	}
+
+	return &pb.BeginPasskeyRegistrationResponse{
+		SessionToken: options.SessionToken,
+		Options:      string(options.Options),
+	}, nil
+}
+
+func (h *Server) FinishPasskeyRegistration(ctx context.Context, req *pb.FinishPasskeyRegistrationRequest) (*pb.FinishPasskeyRegistrationResponse, error) {
+	const op = "delivery.FinishPasskeyRegistration"
+
+	passkey, err := h.passkeyService.FinishPasskeyRegistration(ctx, domain.FinishPasskeyRegistrationParams{
+		SessionToken: req.GetSessionToken(),
+		Credential:   []byte(req.GetCredential()),
+		Name:         req.GetName(),
+	})
+	if err != nil {
+		return nil, passkeyError(op, err)
+	}
+
+	return &pb.FinishPasskeyRegistrationResponse{
+		Passkey: &pb.Passkey{
+			Id:        passkey.ID,
+			Name:      passkey.Name,
+			CreatedAt: timestamppb.New(passkey.CreatedAt),
+		},
+	}, nil
+}
+
+func (h *Server) BeginPasskeyLogin(ctx context.Context, req *pb.BeginPasskeyLoginRequest) (*pb.BeginPasskeyLoginResponse, error) {
+	const op = "delivery.BeginPasskeyLogin"
+
+	options, err := h.passkeyService.BeginPasskeyLogin(ctx)
+	if err != nil {
+		return nil, passkeyError(op, err)
+	}
+
+	return &pb.BeginPasskeyLoginResponse{
+		SessionToken: options.SessionToken,
+		Options:      string(options.Options),
+	}, nil
+}
+
+func (h *Server) FinishPasskeyLogin(ctx context.Context, req *pb.FinishPasskeyLoginRequest) (*pb.FinishPasskeyLoginResponse, error) {
+	const op = "delivery.FinishPasskeyLogin"
+
+	mtdt := h.extractMetadata(ctx)
+
+	tokens, err := h.passkeyService.FinishPasskeyLogin(ctx, domain.FinishPasskeyLoginParams{
+		SessionToken: req.GetSessionToken(),
+		Credential:   []byte(req.GetCredential()),
+		UserAgent:    mtdt.UserAgent,
+		ClientIP:     mtdt.ClientIP,
+	})
+	if err != nil {
+		return nil, passkeyError(op, err)
+	}
+
+	return &pb.FinishPasskeyLoginResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+func passkeyError(op string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+	case errors.Is(err, domain.ErrPasskeysDisabled):
+		return status.Errorf(codes.Unimplemented, "passkeys are disabled")
+	case errors.Is(err, domain.ErrInvalidPasskeyCeremony):
+		return status.Errorf(codes.FailedPrecondition, "invalid or expired passkey ceremony, start again")
+	case errors.Is(err, domain.ErrInvalidPasskeyCredential):
+		// причину пишем в лог, клиенту не подсказываем, что именно не сошлось
+		log.Printf("Passkey verification failed: path: %s, error: %v", op, err)
+		return status.Errorf(codes.Unauthenticated, "passkey verification failed")
+	case errors.Is(err, domain.ErrPasskeyExists):
+		return status.Errorf(codes.AlreadyExists, "passkey already registered")
+	case errors.Is(err, domain.Isemailverified):
+		return status.Errorf(codes.FailedPrecondition, "email is not verified")
+	default:
+		log.Printf("Passkey operation failed: path: %s, error: %v", op, err)
+		return status.Errorf(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,66 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/pkg/worker"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"github.com/hibiken/asynq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *Server) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	const op = "delivery.RequestPasswordReset"
+
+	err := h.authService.RequestPasswordReset(ctx, domain.RequestPasswordResetParams{
+		AppID: req.GetAppId(),
+		Email: req.GetEmail(),
+
+		AfterFind: func(user *pb.User) error {
+			taskPayload := &worker.PayloadSendPasswordReset{
+				UserID: user.Id,
+			}
+
+			opts := []asynq.Option{
+				asynq.MaxRetry(10),
+				asynq.Queue(worker.QueueCritical),
+			}
+
+			// ошибку не возвращаем: ответ для существующего и несуществующего email должен быть одинаковым
+			err := h.taskDistributor.DistributeTaskSendPasswordReset(ctx, taskPayload, opts...)
+			if err != nil {
+				log.Printf("RequestPasswordReset failed to enqueue task: path: %s, error: %v", op, err)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		log.Printf("RequestPasswordReset failed: path: %s, error: %v", op, err)
+		return nil, status.Errorf(codes.Internal, "internal error")
+	}
+
+	return &pb.RequestPasswordResetResponse{}, nil
+}
+
+func (h *Server) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	const op = "delivery.ResetPassword"
+
+	err := h.authService.ResetPassword(ctx, domain.ResetPasswordParams{
+		Token:       req.GetToken(),
+		NewPassword: req.GetNewPassword(),
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPasswordResetToken) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid or expired reset link")
+		}
+		log.Printf("ResetPassword failed: path: %s, error: %v", op, err)
+		return nil, status.Errorf(codes.Internal, "internal error")
+	}
+
+	return &pb.ResetPasswordResponse{}, nil
+}
@@ -0,0 +1,96 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *Server) CreatePersonalAccessToken(ctx context.Context, req *pb.CreatePersonalAccessTokenRequest) (*pb.CreatePersonalAccessTokenResponse, error) {
+	const op = "delivery.CreatePersonalAccessToken"
+
+	created, err := h.authService.CreatePersonalAccessToken(ctx, domain.CreatePersonalAccessTokenParams{
+		Name:   req.GetName(),
+		Scopes: req.GetScopes(),
+		TTL:    time.Duration(req.GetExpiresInSeconds()) * time.Second,
+	})
+	if err != nil {
+		return nil, personalAccessTokenError(op, err)
+	}
+
+	resp := &pb.CreatePersonalAccessTokenResponse{
+		PersonalAccessToken: convertPersonalAccessToken(created.Token),
+		Token:               created.PlainText,
+	}
+
+	return resp, nil
+}
+
+func (h *Server) ListPersonalAccessTokens(ctx context.Context, req *pb.ListPersonalAccessTokensRequest) (*pb.ListPersonalAccessTokensResponse, error) {
+	const op = "delivery.ListPersonalAccessTokens"
+
+	pats, err := h.authService.ListPersonalAccessTokens(ctx)
+	if err != nil {
+		return nil, personalAccessTokenError(op, err)
+	}
+
+	resp := &pb.ListPersonalAccessTokensResponse{
+		PersonalAccessTokens: make([]*pb.PersonalAccessToken, 0, len(pats)),
+	}
+	for _, pat := range pats {
+		resp.PersonalAccessTokens = append(resp.PersonalAccessTokens, convertPersonalAccessToken(pat))
+	}
+
+	return resp, nil
+}
+
+func (h *Server) RevokePersonalAccessToken(ctx context.Context, req *pb.RevokePersonalAccessTokenRequest) (*pb.RevokePersonalAccessTokenResponse, error) {
+	const op = "delivery.RevokePersonalAccessToken"
+
+	err := h.authService.RevokePersonalAccessToken(ctx, req.GetId())
+	if err != nil {
+		return nil, personalAccessTokenError(op, err)
+	}
+
+	return &pb.RevokePersonalAccessTokenResponse{}, nil
+}
+
+func convertPersonalAccessToken(pat *domain.PersonalAccessToken) *pb.PersonalAccessToken {
+	token := &pb.PersonalAccessToken{
+		Id:        pat.ID,
+		Name:      pat.Name,
+		Scopes:    pat.Scopes,
+		CreatedAt: timestamppb.New(pat.CreatedAt),
+	}
+	if pat.LastUsedAt != nil {
+		token.LastUsedAt = timestamppb.New(*pat.LastUsedAt)
+	}
+	if pat.ExpiresAt != nil {
+		token.ExpiresAt = timestamppb.New(*pat.ExpiresAt)
+	}
+	return token
+}
+
+func personalAccessTokenError(op string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+	case errors.Is(err, domain.ErrPermissionDenied):
+		return status.Errorf(codes.PermissionDenied, "personal access tokens can only be created from a login session")
+	case errors.Is(err, domain.ErrInvalidPersonalAccessToken):
+		return status.Errorf(codes.InvalidArgument, "%s", err)
+	case errors.Is(err, domain.ErrPersonalAccessTokenNotFound):
+		return status.Errorf(codes.NotFound, "personal access token not found")
+	default:
+		log.Printf("Personal access token operation failed: path: %s, error: %v", op, err)
+		return status.Errorf(codes.Internal, "internal error")
+	}
+}
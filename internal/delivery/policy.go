@@ -0,0 +1,59 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// проверка доступа по CEL политикам; запрет - не ошибка, а allowed=false с объяснением
+func (h *Server) Authorize(ctx context.Context, req *pb.AuthorizeRequest) (*pb.AuthorizeResponse, error) {
+	const op = "delivery.Authorize"
+
+	caller, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subjectID := req.GetSubjectId()
+	if subjectID == 0 {
+		subjectID = caller.Id
+	}
+
+	// чужой доступ проверяют только сервисы с отдельным правом
+	if subjectID != caller.Id {
+		if err := h.rbacService.Authorize(ctx, caller.Id, "", domain.PermissionPoliciesEvaluate); err != nil {
+			return nil, rbacError(op, err)
+		}
+	}
+
+	decision, err := h.policyService.Authorize(ctx, domain.AuthorizeParams{
+		SubjectID:    subjectID,
+		Action:       req.GetAction(),
+		ResourceType: req.GetResource().GetType(),
+		ResourceID:   req.GetResource().GetId(),
+		Attributes:   req.GetAttributes().AsMap(),
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "subject or resource user not found")
+		}
+		log.Printf("Policy evaluation failed: path: %s, error: %v", op, err)
+		return nil, status.Errorf(codes.Internal, "failed to evaluate policies")
+	}
+
+	resp := &pb.AuthorizeResponse{
+		Allowed: decision.Allowed,
+		Policy:  decision.Policy,
+		Effect:  decision.Effect,
+		Reason:  decision.Reason,
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,281 @@
+package gapi
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Iowel/app-auth-service/pkg/cache"
+	"github.com/Iowel/app-auth-service/pkg/configs"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+	pbv2 "github.com/Iowel/app-auth-service/pkg/pb/v2"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// лимиты по умолчанию для ручек, которые можно дергать без авторизации
+var defaultRateLimits = map[string]configs.MethodRateLimit{
+	pb.AuthService_RegisterUser_FullMethodName: {
+		PerIP:    configs.RateLimit{Limit: 10, Window: time.Hour},
+		PerEmail: configs.RateLimit{Limit: 3, Window: time.Hour},
+	},
+	// по email держим с запасом над блокировкой после неудачных логинов, чтобы сначала срабатывала она
+	pb.AuthService_LoginUser_FullMethodName: {
+		PerIP:    configs.RateLimit{Limit: 60, Window: time.Minute},
+		PerEmail: configs.RateLimit{Limit: 20, Window: time.Minute},
+	},
+	pb.AuthService_VerifyEmail_FullMethodName: {
+		PerIP: configs.RateLimit{Limit: 20, Window: time.Minute},
+	},
+	pb.AuthService_VerifyTwoFactor_FullMethodName: {
+		PerIP: configs.RateLimit{Limit: 30, Window: time.Minute},
+	},
+	pb.AuthService_RequestPasswordReset_FullMethodName: {
+		PerIP:    configs.RateLimit{Limit: 10, Window: time.Hour},
+		PerEmail: configs.RateLimit{Limit: 3, Window: time.Hour},
+	},
+	pb.AuthService_ResetPassword_FullMethodName: {
+		PerIP: configs.RateLimit{Limit: 20, Window: time.Hour},
+	},
+	pb.AuthService_RequestMagicLink_FullMethodName: {
+		PerIP:    configs.RateLimit{Limit: 10, Window: time.Hour},
+		PerEmail: configs.RateLimit{Limit: 3, Window: time.Hour},
+	},
+	pb.AuthService_ConsumeMagicLink_FullMethodName: {
+		PerIP: configs.RateLimit{Limit: 20, Window: time.Minute},
+	},
+}
+
+// неудачные аутентификации с одного IP: перебор токенов, кодов и секретов клиентов.
+// считаем только отказы, иначе упремся в нормальный трафик сервисов, которые проверяют токены
+const (
+	authFailuresLimit = "AuthFailures"
+	// forward auth дергает reverse proxy, так что все его клиенты приходят с одного адреса
+	forwardAuthFailuresLimit = "ForwardAuth"
+)
+
+// держим выше блокировки логина по IP, чтобы для логина сначала срабатывала она
+var defaultFailureLimits = map[string]configs.RateLimit{
+	authFailuresLimit:        {Limit: 60, Window: time.Minute},
+	forwardAuthFailuresLimit: {Limit: 600, Window: time.Minute},
+}
+
+type rateLimiter struct {
+	limiter  cache.IRateLimiter
+	limits   map[string]configs.MethodRateLimit
+	failures map[string]configs.RateLimit
+}
+
+func newRateLimiter(limiter cache.IRateLimiter, cfg configs.RateLimitConfig) *rateLimiter {
+	limits := make(map[string]configs.MethodRateLimit, len(defaultRateLimits))
+	for method, limit := range defaultRateLimits {
+		limits[method] = limit
+	}
+
+	// v2 считает в те же счетчики, что и v1 (ключ по короткому имени метода), иначе лимит обходится сменой версии
+	limits[pbv2.AuthService_RegisterUser_FullMethodName] = limits[pb.AuthService_RegisterUser_FullMethodName]
+	limits[pbv2.AuthService_LoginUser_FullMethodName] = limits[pb.AuthService_LoginUser_FullMethodName]
+
+	failures := make(map[string]configs.RateLimit, len(defaultFailureLimits))
+	for name, limit := range defaultFailureLimits {
+		failures[name] = limit
+	}
+
+	// в конфиге короткие имена методов, они общие для v1 и v2; лимиты на отказы там же, по IP
+	for method, limit := range cfg.Methods {
+		if _, ok := failures[method]; ok {
+			failures[method] = limit.PerIP
+			continue
+		}
+		limits["/"+pb.AuthService_ServiceDesc.ServiceName+"/"+method] = limit
+		limits["/"+pbv2.AuthService_ServiceDesc.ServiceName+"/"+method] = limit
+	}
+
+	return &rateLimiter{
+		limiter:  limiter,
+		limits:   limits,
+		failures: failures,
+	}
+}
+
+// unary интерцептор; gateway ходит в gRPC сервер клиентом, так что HTTP трафик тоже через него проходит
+func NewRateLimitInterceptor(limiter cache.IRateLimiter, cfg configs.RateLimitConfig) grpc.UnaryServerInterceptor {
+	return newRateLimiter(limiter, cfg).intercept
+}
+
+func (r *rateLimiter) intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ip := clientHost(metadataFromContext(ctx).ClientIP)
+
+	// проверяем до аутентификации: иначе угаданный токен пройдет, даже когда лимит исчерпан
+	if ip != "" {
+		if err := r.checkFailures(ctx, authFailuresLimit, ip); err != nil {
+			return nil, err
+		}
+	}
+
+	if limits, ok := r.limits[info.FullMethod]; ok {
+		method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+
+		if ip != "" {
+			if err := r.allow(ctx, method+":ip:"+ip, limits.PerIP); err != nil {
+				return nil, err
+			}
+		}
+
+		if withEmail, ok := req.(interface{ GetEmail() string }); ok {
+			if email := strings.ToLower(strings.TrimSpace(withEmail.GetEmail())); email != "" {
+				if err := r.allow(ctx, method+":email:"+email, limits.PerEmail); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	resp, err := handler(ctx, req)
+	if ip != "" && status.Code(err) == codes.Unauthenticated {
+		r.registerFailure(ctx, authFailuresLimit, ip)
+	}
+
+	return resp, err
+}
+
+// для HTTP ручек, которые не проходят через gRPC: 400, 401 и 403 (например, кривой csrf в согласии) считаем неудачной попыткой
+func (r *rateLimiter) limitFailures(name string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ip := clientHost(httpMetadata(req).ClientIP)
+
+		if err := r.checkFailures(req.Context(), name, ip); err != nil {
+			writeRateLimitError(w, err)
+			return
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, req)
+
+		switch rec.status {
+		case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+			r.registerFailure(req.Context(), name, ip)
+		}
+	}
+}
+
+func (r *rateLimiter) checkFailures(ctx context.Context, name, ip string) error {
+	limit := r.failures[name]
+	if limit.Limit <= 0 || limit.Window <= 0 {
+		return nil
+	}
+
+	// как и в allow: без redis не блокируем
+	retryAfter, err := r.limiter.RetryAfter(ctx, failuresKey(name, ip), limit.Limit, limit.Window)
+	if err != nil {
+		log.Printf("rate limit check failed: %s, error: %s", failuresKey(name, ip), err)
+		return nil
+	}
+
+	return rateLimitError(retryAfter)
+}
+
+func (r *rateLimiter) registerFailure(ctx context.Context, name, ip string) {
+	limit := r.failures[name]
+	if limit.Limit <= 0 || limit.Window <= 0 {
+		return
+	}
+
+	// лимит уже исчерпан - попытка все равно не прошла бы checkFailures, отдельно ее не учитываем
+	if _, err := r.limiter.Allow(ctx, failuresKey(name, ip), limit.Limit, limit.Window); err != nil {
+		log.Printf("rate limit update failed: %s, error: %s", failuresKey(name, ip), err)
+	}
+}
+
+func failuresKey(name, ip string) string {
+	return "ratelimit:" + name + ":ip:" + ip
+}
+
+func (r *rateLimiter) allow(ctx context.Context, key string, limit configs.RateLimit) error {
+	if limit.Limit <= 0 || limit.Window <= 0 {
+		return nil
+	}
+
+	// redis недоступен - пропускаем, иначе его падение уронит регистрацию и логин
+	retryAfter, err := r.limiter.Allow(ctx, "ratelimit:"+key, limit.Limit, limit.Window)
+	if err != nil {
+		log.Printf("rate limit check failed: %s, error: %s", key, err)
+		return nil
+	}
+
+	return rateLimitError(retryAfter)
+}
+
+func rateLimitError(retryAfter time.Duration) error {
+	if retryAfter <= 0 {
+		return nil
+	}
+
+	st, err := status.New(codes.ResourceExhausted, "too many requests").WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter),
+	})
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, "too many requests")
+	}
+
+	return st.Err()
+}
+
+// для ручек вне gateway отвечаем сами, в формате OAuth ошибок
+func writeRateLimitError(w http.ResponseWriter, err error) {
+	setRetryAfter(w, err)
+	writeJSON(w, http.StatusTooManyRequests, oauthError{Error: "too_many_requests"})
+}
+
+// запоминает код ответа, чтобы после хендлера понять, была ли попытка неудачной
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// для gateway: RetryInfo из ошибки превращаем в заголовок Retry-After
+func rateLimitErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	if status.Code(err) == codes.ResourceExhausted {
+		setRetryAfter(w, err)
+	}
+
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+}
+
+// заголовок Retry-After из RetryInfo в деталях ошибки
+func setRetryAfter(w http.ResponseWriter, err error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return
+	}
+
+	for _, detail := range st.Details() {
+		if retryInfo, ok := detail.(*errdetails.RetryInfo); ok {
+			seconds := math.Ceil(retryInfo.GetRetryDelay().AsDuration().Seconds())
+			w.Header().Set("Retry-After", strconv.Itoa(int(seconds)))
+		}
+	}
+}
+
+// из peer приходит адрес вместе с портом
+func clientHost(clientIP string) string {
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		return host
+	}
+	return clientIP
+}
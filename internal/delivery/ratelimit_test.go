@@ -0,0 +1,119 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Iowel/app-auth-service/pkg/configs"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// счетчики в памяти вместо redis, окно не учитываем
+type fakeRateLimiter struct {
+	counts map[string]int64
+}
+
+func (f *fakeRateLimiter) Allow(ctx context.Context, key string, limit int64, window time.Duration) (time.Duration, error) {
+	if f.counts[key] >= limit {
+		return window, nil
+	}
+	f.counts[key]++
+	return 0, nil
+}
+
+func (f *fakeRateLimiter) RetryAfter(ctx context.Context, key string, limit int64, window time.Duration) (time.Duration, error) {
+	if f.counts[key] >= limit {
+		return window, nil
+	}
+	return 0, nil
+}
+
+func newTestRateLimiter(failures int64) (*rateLimiter, *fakeRateLimiter) {
+	fake := &fakeRateLimiter{counts: make(map[string]int64)}
+	cfg := configs.RateLimitConfig{Methods: map[string]configs.MethodRateLimit{
+		authFailuresLimit: {PerIP: configs.RateLimit{Limit: failures, Window: time.Minute}},
+	}}
+	return newRateLimiter(fake, cfg), fake
+}
+
+func TestLimitFailuresHTTP(t *testing.T) {
+	limits, fake := newTestRateLimiter(2)
+
+	valid := false
+	handler := limits.limitFailures(authFailuresLimit, func(w http.ResponseWriter, r *http.Request) {
+		if valid {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	do := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/oauth/token", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	// успешные ответы в счетчик не попадают
+	valid = true
+	for i := 0; i < 3; i++ {
+		if rec := do(); rec.Code != http.StatusOK {
+			t.Fatalf("valid request %d: status %d, want 200", i, rec.Code)
+		}
+	}
+
+	valid = false
+	for i := 0; i < 2; i++ {
+		if rec := do(); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("failed request %d: status %d, want 401", i, rec.Code)
+		}
+	}
+
+	// лимит исчерпан - даже верные данные не доходят до хендлера
+	valid = true
+	rec := do()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("Retry-After = %q, want 60", got)
+	}
+
+	if got := fake.counts[failuresKey(authFailuresLimit, "192.0.2.1")]; got != 2 {
+		t.Errorf("failures counted = %d, want 2", got)
+	}
+}
+
+func TestInterceptCountsUnauthenticated(t *testing.T) {
+	limits, _ := newTestRateLimiter(1)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}})
+	info := &grpc.UnaryServerInfo{FullMethod: pb.AuthService_VerifyToken_FullMethodName}
+
+	calls := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return nil, status.Error(codes.Unauthenticated, "failed to verify token")
+	}
+
+	if _, err := limits.intercept(ctx, &pb.VerifyTokenRequest{}, info, handler); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("first call: %v, want Unauthenticated", err)
+	}
+	if _, err := limits.intercept(ctx, &pb.VerifyTokenRequest{}, info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second call: %v, want ResourceExhausted", err)
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
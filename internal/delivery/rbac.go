@@ -0,0 +1,151 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *Server) ListRoles(ctx context.Context, req *pb.ListRolesRequest) (*pb.ListRolesResponse, error) {
+	const op = "delivery.ListRoles"
+
+	roles, err := h.rbacService.ListRoles(ctx)
+	if err != nil {
+		return nil, rbacError(op, err)
+	}
+
+	resp := &pb.ListRolesResponse{
+		Roles: make([]*pb.Role, 0, len(roles)),
+	}
+	for _, role := range roles {
+		resp.Roles = append(resp.Roles, convertRole(role))
+	}
+
+	return resp, nil
+}
+
+func (h *Server) CreateRole(ctx context.Context, req *pb.CreateRoleRequest) (*pb.CreateRoleResponse, error) {
+	const op = "delivery.CreateRole"
+
+	role, err := h.rbacService.CreateRole(ctx, domain.CreateRoleParams{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Permissions: req.GetPermissions(),
+	})
+	if err != nil {
+		return nil, rbacError(op, err)
+	}
+
+	return &pb.CreateRoleResponse{Role: convertRole(role)}, nil
+}
+
+func (h *Server) DeleteRole(ctx context.Context, req *pb.DeleteRoleRequest) (*pb.DeleteRoleResponse, error) {
+	const op = "delivery.DeleteRole"
+
+	if err := h.rbacService.DeleteRole(ctx, req.GetName()); err != nil {
+		return nil, rbacError(op, err)
+	}
+
+	return &pb.DeleteRoleResponse{}, nil
+}
+
+func (h *Server) GrantRolePermission(ctx context.Context, req *pb.GrantRolePermissionRequest) (*pb.GrantRolePermissionResponse, error) {
+	const op = "delivery.GrantRolePermission"
+
+	if err := h.rbacService.GrantPermission(ctx, req.GetRole(), req.GetPermission()); err != nil {
+		return nil, rbacError(op, err)
+	}
+
+	return &pb.GrantRolePermissionResponse{}, nil
+}
+
+func (h *Server) RevokeRolePermission(ctx context.Context, req *pb.RevokeRolePermissionRequest) (*pb.RevokeRolePermissionResponse, error) {
+	const op = "delivery.RevokeRolePermission"
+
+	if err := h.rbacService.RevokePermission(ctx, req.GetRole(), req.GetPermission()); err != nil {
+		return nil, rbacError(op, err)
+	}
+
+	return &pb.RevokeRolePermissionResponse{}, nil
+}
+
+func (h *Server) AssignUserRole(ctx context.Context, req *pb.AssignUserRoleRequest) (*pb.AssignUserRoleResponse, error) {
+	const op = "delivery.AssignUserRole"
+
+	if err := h.rbacService.AssignRole(ctx, req.GetUserId(), req.GetRole()); err != nil {
+		return nil, rbacError(op, err)
+	}
+
+	return &pb.AssignUserRoleResponse{}, nil
+}
+
+func (h *Server) RevokeUserRole(ctx context.Context, req *pb.RevokeUserRoleRequest) (*pb.RevokeUserRoleResponse, error) {
+	const op = "delivery.RevokeUserRole"
+
+	admin, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// иначе единственный админ может случайно остаться без доступа к этим ручкам
+	if admin.Id == req.GetUserId() {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot revoke your own role")
+	}
+
+	if err := h.rbacService.RevokeRole(ctx, req.GetUserId(), req.GetRole()); err != nil {
+		return nil, rbacError(op, err)
+	}
+
+	return &pb.RevokeUserRoleResponse{}, nil
+}
+
+func (h *Server) GetUserPermissions(ctx context.Context, req *pb.GetUserPermissionsRequest) (*pb.GetUserPermissionsResponse, error) {
+	const op = "delivery.GetUserPermissions"
+
+	perms, err := h.rbacService.UserPermissions(ctx, req.GetUserId())
+	if err != nil {
+		return nil, rbacError(op, err)
+	}
+
+	resp := &pb.GetUserPermissionsResponse{
+		Roles:       perms.Roles,
+		Permissions: perms.Permissions,
+	}
+
+	return resp, nil
+}
+
+func convertRole(role *domain.Role) *pb.Role {
+	return &pb.Role{
+		Id:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+		CreatedAt:   timestamppb.New(role.CreatedAt),
+	}
+}
+
+func rbacError(op string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrPermissionDenied):
+		return status.Errorf(codes.PermissionDenied, "permission denied")
+	case errors.Is(err, domain.ErrRoleNotFound):
+		return status.Errorf(codes.NotFound, "role not found")
+	case errors.Is(err, domain.ErrRoleExists):
+		return status.Errorf(codes.AlreadyExists, "role already exists")
+	case errors.Is(err, domain.ErrInvalidRoleName):
+		return status.Errorf(codes.InvalidArgument, "%s", err)
+	case errors.Is(err, domain.ErrUserNotFound):
+		return status.Errorf(codes.NotFound, "user not found")
+	default:
+		log.Printf("RBAC operation failed: path: %s, error: %v", op, err)
+		return status.Errorf(codes.Internal, "internal error")
+	}
+}
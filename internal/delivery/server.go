@@ -11,14 +11,17 @@ import (
 
 	"github.com/Iowel/app-auth-service/internal/pkg/worker"
 	"github.com/Iowel/app-auth-service/internal/service"
+	"github.com/Iowel/app-auth-service/pkg/cache"
 	"github.com/Iowel/app-auth-service/pkg/configs"
 	pb "github.com/Iowel/app-auth-service/pkg/pb"
+	pbv2 "github.com/Iowel/app-auth-service/pkg/pb/v2"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/cors"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -30,31 +33,62 @@ type Server struct {
 	authService     service.IAuthService
 	mailService     service.IMailService
 	taskDistributor worker.TaskDistributor
+	keyService      service.IKeyService
+	oauthService    service.IOAuthService
+	passkeyService  service.IPasskeyService
+	rbacService     service.IRBACService
+	policyService   service.IPolicyService
 }
 
-func NewServer(cfg *configs.Config, db *pgxpool.Pool, authService service.IAuthService, mailService service.IMailService, taskDistrib worker.TaskDistributor) (*Server, error) {
+// сервисы, которые добавлялись к серверу по мере роста API; базовые идут отдельными аргументами
+type ServerDeps struct {
+	KeyService     service.IKeyService
+	OAuthService   service.IOAuthService
+	PasskeyService service.IPasskeyService
+	RBACService    service.IRBACService
+	PolicyService  service.IPolicyService
+	RateLimiter    cache.IRateLimiter
+}
+
+func NewServer(cfg *configs.Config, db *pgxpool.Pool, authService service.IAuthService, mailService service.IMailService, taskDistrib worker.TaskDistributor, deps ServerDeps) (*Server, error) {
 	server := &Server{
 		cfg:             cfg,
 		db:              db,
 		authService:     authService,
 		mailService:     mailService,
 		taskDistributor: taskDistrib,
+		keyService:      deps.KeyService,
+		oauthService:    deps.OAuthService,
+		passkeyService:  deps.PasskeyService,
+		rbacService:     deps.RBACService,
+		policyService:   deps.PolicyService,
 	}
 	return server, nil
 }
 
-func RunGrpcServer(ctx context.Context, waitGroup *errgroup.Group, cfg *configs.Config, db *pgxpool.Pool, authService service.IAuthService, mailService service.IMailService, taskDistrib worker.TaskDistributor) {
+func RunGrpcServer(ctx context.Context, waitGroup *errgroup.Group, cfg *configs.Config, db *pgxpool.Pool, authService service.IAuthService, mailService service.IMailService, taskDistrib worker.TaskDistributor, deps ServerDeps) {
 	const op = "delivery.server.RunGrpcServer"
 
-	server, err := NewServer(cfg, db, authService, mailService, taskDistrib)
+	server, err := NewServer(cfg, db, authService, mailService, taskDistrib, deps)
 	if err != nil {
 		log.Fatal("cannot create server")
 	}
 
-	grpcLogger := grpc.UnaryInterceptor(GrpcLogger)
-
-	grpcServer := grpc.NewServer(grpcLogger)
+	// аутентификация после лимитера: он видит ее отказы и отсекает перебор токенов с одного IP
+	interceptors := grpc.ChainUnaryInterceptor(
+		GrpcLogger,
+		NewRateLimitInterceptor(deps.RateLimiter, cfg.RateLimit),
+		NewAuthInterceptor(authService, deps.RBACService),
+		// после аутентификации: неавторизованный клиент получает Unauthenticated, а не разбор своих полей
+		ValidateRequest,
+	)
+	streamInterceptors := grpc.ChainStreamInterceptor(
+		NewAuthStreamInterceptor(authService, deps.RBACService),
+	)
+
+	grpcServer := grpc.NewServer(interceptors, streamInterceptors)
 	pb.RegisterAuthServiceServer(grpcServer, server)
+	pbv2.RegisterAuthServiceServer(grpcServer, NewServerV2(server))
 	reflection.Register(grpcServer)
 
 	listener, err := net.Listen("tcp", cfg.Grpc.Port)
@@ -89,10 +123,10 @@ func RunGrpcServer(ctx context.Context, waitGroup *errgroup.Group, cfg *configs.
 	})
 }
 
-func RunGatewayServer(ctx context.Context, waitGroup *errgroup.Group, authService service.IAuthService, mailService service.IMailService, cfg *configs.Config, db *pgxpool.Pool, taskDistrib worker.TaskDistributor) {
+func RunGatewayServer(ctx context.Context, waitGroup *errgroup.Group, authService service.IAuthService, mailService service.IMailService, cfg *configs.Config, db *pgxpool.Pool, taskDistrib worker.TaskDistributor, deps ServerDeps) {
 	const op = "delivery.server.RunGatewayServer"
 
-	server, err := NewServer(cfg, db, authService, mailService, taskDistrib)
+	server, err := NewServer(cfg, db, authService, mailService, taskDistrib, deps)
 	if err != nil {
 		log.Fatalf("cannot create server, path: %s, error: %v\n", op, err)
 	}
@@ -106,17 +140,45 @@ func RunGatewayServer(ctx context.Context, waitGroup *errgroup.Group, authServic
 		},
 	})
 
-	grpcMux := runtime.NewServeMux(jsonOption)
+	grpcMux := runtime.NewServeMux(jsonOption, runtime.WithErrorHandler(rateLimitErrorHandler))
 
-	// регистрируем gRPC gateway хендлер
-	err = pb.RegisterAuthServiceHandlerServer(ctx, grpcMux, server)
+	// gateway ходит в наш же gRPC сервер, а не вызывает хендлеры напрямую - иначе мимо него пройдут интерцепторы
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	err = pb.RegisterAuthServiceHandlerFromEndpoint(ctx, grpcMux, grpcDialAddress(cfg.Grpc.Port), dialOpts)
 	if err != nil {
 		log.Fatalf("cannot register gRPC gateway handler, path: %s, error: %v\n", op, err)
 	}
+	err = pbv2.RegisterAuthServiceHandlerFromEndpoint(ctx, grpcMux, grpcDialAddress(cfg.Grpc.Port), dialOpts)
+	if err != nil {
+		log.Fatalf("cannot register gRPC gateway v2 handler, path: %s, error: %v\n", op, err)
+	}
+
+	// эти ручки идут мимо gRPC интерцепторов, неудачные попытки считаем здесь
+	limits := newRateLimiter(deps.RateLimiter, cfg.RateLimit)
 
 	mux := http.NewServeMux()
 	mux.Handle("/", grpcMux)
 
+	// публичные ключи для проверки JWT другими сервисами
+	mux.HandleFunc("GET /.well-known/jwks.json", server.JWKS)
+
+	// OAuth 2.0 token introspection (RFC 7662)
+	mux.HandleFunc("POST /oauth/introspect", limits.limitFailures(authFailuresLimit, server.Introspect))
+
+	// OAuth 2.0 authorization code + PKCE для сторонних и наших веб-приложений
+	mux.HandleFunc("GET /oauth/authorize", server.OAuthAuthorize)
+	// на POST приходит согласие: проверяем csrf и токен на каждый вызов, отказы считаем
+	mux.HandleFunc("POST /oauth/authorize", limits.limitFailures(authFailuresLimit, server.OAuthAuthorize))
+	mux.HandleFunc("POST /oauth/token", limits.limitFailures(authFailuresLimit, server.Token))
+
+	// OpenID Connect
+	mux.HandleFunc("GET /.well-known/openid-configuration", server.OpenIDConfiguration)
+	mux.HandleFunc("GET /userinfo", limits.limitFailures(authFailuresLimit, server.UserInfo))
+	mux.HandleFunc("POST /userinfo", limits.limitFailures(authFailuresLimit, server.UserInfo))
+
+	// проверка токена для reverse proxy, которые не умеют в gRPC
+	mux.HandleFunc("GET /auth/forward", limits.limitFailures(forwardAuthFailuresLimit, server.ForwardAuth))
+
 	// TODO: вынести в конфиг
 	allowedOrigins := []string{
 		"http://localhost:8081",
@@ -181,3 +243,17 @@ func RunGatewayServer(ctx context.Context, waitGroup *errgroup.Group, authServic
 	})
 
 }
+
+// сервер слушает ":9090" или "0.0.0.0:9090", а подключаться к нему надо по конкретному адресу
+func grpcDialAddress(listenAddr string) string {
+	host, port, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		return listenAddr
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+
+	return net.JoinHostPort(host, port)
+}
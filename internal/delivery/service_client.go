@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *Server) RegisterServiceClient(ctx context.Context, req *pb.RegisterServiceClientRequest) (*pb.RegisterServiceClientResponse, error) {
+	const op = "delivery.RegisterServiceClient"
+
+	creds, err := h.oauthService.RegisterServiceClient(ctx, domain.RegisterServiceClientParams{
+		Name:   req.GetName(),
+		Scopes: req.GetScopes(),
+	})
+	if err != nil {
+		return nil, serviceClientError(op, err)
+	}
+
+	resp := &pb.RegisterServiceClientResponse{
+		Client:       convertServiceClient(creds.Client),
+		ClientSecret: creds.ClientSecret,
+	}
+
+	return resp, nil
+}
+
+func (h *Server) RotateServiceClientSecret(ctx context.Context, req *pb.RotateServiceClientSecretRequest) (*pb.RotateServiceClientSecretResponse, error) {
+	const op = "delivery.RotateServiceClientSecret"
+
+	creds, err := h.oauthService.RotateServiceClientSecret(ctx, req.GetClientId())
+	if err != nil {
+		return nil, serviceClientError(op, err)
+	}
+
+	resp := &pb.RotateServiceClientSecretResponse{
+		Client:       convertServiceClient(creds.Client),
+		ClientSecret: creds.ClientSecret,
+	}
+
+	return resp, nil
+}
+
+func convertServiceClient(client *domain.OAuthClient) *pb.ServiceClient {
+	c := &pb.ServiceClient{
+		ClientId:  client.ClientID,
+		Name:      client.Name,
+		Scopes:    client.AllowedScopes,
+		CreatedAt: timestamppb.New(client.CreatedAt),
+	}
+	if client.SecretRotatedAt != nil {
+		c.SecretRotatedAt = timestamppb.New(*client.SecretRotatedAt)
+	}
+	return c
+}
+
+func serviceClientError(op string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidServiceClient), errors.Is(err, domain.ErrInvalidScope):
+		return status.Errorf(codes.InvalidArgument, "%s", err)
+	case errors.Is(err, domain.ErrInvalidClient):
+		return status.Errorf(codes.NotFound, "service client not found")
+	default:
+		log.Printf("Service client operation failed: path: %s, error: %v", op, err)
+		return status.Errorf(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *Server) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	const op = "delivery.ListSessions"
+
+	sessions, currentID, err := h.authService.ListSessions(ctx)
+	if err != nil {
+		return nil, sessionError(op, err)
+	}
+
+	resp := &pb.ListSessionsResponse{
+		Sessions: make([]*pb.Session, 0, len(sessions)),
+	}
+	for _, s := range sessions {
+		resp.Sessions = append(resp.Sessions, &pb.Session{
+			Id:         s.ID,
+			UserAgent:  s.UserAgent,
+			ClientIp:   s.ClientIP,
+			CreatedAt:  timestamppb.New(s.CreatedAt),
+			LastUsedAt: timestamppb.New(s.LastUsedAt),
+			ExpiresAt:  timestamppb.New(s.ExpiresAt),
+			Current:    s.ID == currentID,
+		})
+	}
+
+	return resp, nil
+}
+
+func (h *Server) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	const op = "delivery.RevokeSession"
+
+	err := h.authService.RevokeSession(ctx, req.GetSessionId())
+	if err != nil {
+		return nil, sessionError(op, err)
+	}
+
+	return &pb.RevokeSessionResponse{}, nil
+}
+
+func (h *Server) RevokeAllOtherSessions(ctx context.Context, req *pb.RevokeAllOtherSessionsRequest) (*pb.RevokeAllOtherSessionsResponse, error) {
+	const op = "delivery.RevokeAllOtherSessions"
+
+	revoked, err := h.authService.RevokeAllOtherSessions(ctx)
+	if err != nil {
+		return nil, sessionError(op, err)
+	}
+
+	return &pb.RevokeAllOtherSessionsResponse{RevokedCount: revoked}, nil
+}
+
+func sessionError(op string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+	case errors.Is(err, domain.ErrSessionNotFound):
+		return status.Errorf(codes.NotFound, "session not found")
+	default:
+		log.Printf("Session operation failed: path: %s, error: %v", op, err)
+		return status.Errorf(codes.Internal, "internal error")
+	}
+}
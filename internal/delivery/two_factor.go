@@ -0,0 +1,89 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *Server) EnrollTwoFactor(ctx context.Context, req *pb.EnrollTwoFactorRequest) (*pb.EnrollTwoFactorResponse, error) {
+	const op = "delivery.EnrollTwoFactor"
+
+	enrollment, err := h.authService.EnrollTwoFactor(ctx)
+	if err != nil {
+		return nil, twoFactorError(op, err)
+	}
+
+	return &pb.EnrollTwoFactorResponse{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.OTPAuthURI,
+	}, nil
+}
+
+func (h *Server) ConfirmTwoFactor(ctx context.Context, req *pb.ConfirmTwoFactorRequest) (*pb.ConfirmTwoFactorResponse, error) {
+	const op = "delivery.ConfirmTwoFactor"
+
+	recoveryCodes, err := h.authService.ConfirmTwoFactor(ctx, req.GetCode())
+	if err != nil {
+		return nil, twoFactorError(op, err)
+	}
+
+	return &pb.ConfirmTwoFactorResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (h *Server) VerifyTwoFactor(ctx context.Context, req *pb.VerifyTwoFactorRequest) (*pb.VerifyTwoFactorResponse, error) {
+	const op = "delivery.VerifyTwoFactor"
+
+	tokens, err := h.authService.VerifyTwoFactor(ctx, domain.VerifyTwoFactorParams{
+		ChallengeToken: req.GetChallengeToken(),
+		Code:           req.GetCode(),
+	})
+	if err != nil {
+		return nil, twoFactorError(op, err)
+	}
+
+	return &pb.VerifyTwoFactorResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+func (h *Server) DisableTwoFactor(ctx context.Context, req *pb.DisableTwoFactorRequest) (*pb.DisableTwoFactorResponse, error) {
+	const op = "delivery.DisableTwoFactor"
+
+	err := h.authService.DisableTwoFactor(ctx, req.GetCode())
+	if err != nil {
+		return nil, twoFactorError(op, err)
+	}
+
+	return &pb.DisableTwoFactorResponse{}, nil
+}
+
+func twoFactorError(op string, err error) error {
+	var throttled *domain.LoginThrottledError
+
+	switch {
+	case errors.As(err, &throttled):
+		return status.Errorf(codes.ResourceExhausted, "too many attempts, retry in %d seconds", int64(math.Ceil(throttled.RetryAfter.Seconds())))
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+	case errors.Is(err, domain.ErrInvalidTwoFactorChallenge):
+		return status.Errorf(codes.Unauthenticated, "invalid or expired challenge, log in again")
+	case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+		return status.Errorf(codes.InvalidArgument, "invalid code")
+	case errors.Is(err, domain.ErrTwoFactorAlreadyEnabled):
+		return status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	case errors.Is(err, domain.ErrTwoFactorNotEnabled):
+		return status.Errorf(codes.FailedPrecondition, "two-factor authentication is not enabled")
+	default:
+		log.Printf("Two-factor operation failed: path: %s, error: %v", op, err)
+		return status.Errorf(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,30 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/Iowel/app-auth-service/internal/pkg/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+)
+
+// проверяем запрос до хендлера и сервисов; все нарушения отдаем одним InvalidArgument
+func ValidateRequest(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	violations := validator.Request(req)
+	if len(violations) == 0 {
+		return handler(ctx, req)
+	}
+
+	fieldViolations := make([]*errdetails.BadRequest_FieldViolation, 0, len(violations))
+	for _, violation := range violations {
+		fieldViolations = append(fieldViolations, fieldViolation(violation.Field, violation.Err))
+	}
+
+	return nil, invalidArgumentError(fieldViolations)
+}
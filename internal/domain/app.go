@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// приложение, в которое попадают старые юзвери и запросы без app_id там, где он не обязателен
+const DefaultAppID int64 = 1
+
+// приложение (тенант): юзвери и их email'ы изолированы внутри него
+type App struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 0 в необязательных app_id значит приложение по умолчанию
+func AppIDOrDefault(appID int64) int64 {
+	if appID == 0 {
+		return DefaultAppID
+	}
+	return appID
+}
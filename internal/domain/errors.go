@@ -9,13 +9,62 @@ const (
 var (
 	ErrWrongCredentials = errors.New("wrong email or password")
 	ErrUserNotFound     = errors.New("user not found")
+	ErrProfileNotFound  = errors.New("profile not found")
 	ErrUserExists       = errors.New("user already exists")
 	ErrQueryFailed      = errors.New("query failed")
 
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrInvalidAppID       = errors.New("invalid app_id")
 
+	// смена email или пароля без текущего пароля
+	ErrCurrentPasswordRequired = errors.New("current password is required")
+
 	ErrVerifyEmailNotFound = errors.New("verification record not found or expired")
 
+	ErrTokenNotFound       = errors.New("token not found or expired")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+
+	ErrSessionNotFound = errors.New("session not found")
+	ErrUnauthenticated = errors.New("unauthenticated")
+
+	ErrPersonalAccessTokenNotFound = errors.New("personal access token not found")
+	ErrInvalidPersonalAccessToken  = errors.New("personal access token needs a name and at least one scope")
+
+	ErrJWTDisabled = errors.New("jwt tokens are disabled")
+
+	// ошибки OAuth 2.0, в ответе превращаются в коды из RFC 6749
+	ErrInvalidClient           = errors.New("invalid client")
+	ErrInvalidRedirectURI      = errors.New("invalid redirect_uri")
+	ErrInvalidOAuthRequest     = errors.New("invalid oauth request")
+	ErrInvalidScope            = errors.New("invalid scope")
+	ErrInvalidGrant            = errors.New("invalid grant")
+	ErrUnsupportedGrantType    = errors.New("unsupported grant type")
+	ErrUnsupportedResponseType = errors.New("unsupported response type")
+	ErrInsufficientScope       = errors.New("insufficient scope")
+	ErrUnauthorizedClient      = errors.New("client is not allowed to use this grant type")
+	ErrInvalidServiceClient    = errors.New("service client needs a name and at least one scope")
+
+	ErrTwoFactorNotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrInvalidTwoFactorCode      = errors.New("invalid two-factor code")
+	ErrInvalidTwoFactorChallenge = errors.New("invalid or expired two-factor challenge")
+
+	ErrPasskeysDisabled         = errors.New("passkeys are disabled")
+	ErrInvalidPasskeyCeremony   = errors.New("invalid or expired passkey ceremony")
+	ErrInvalidPasskeyCredential = errors.New("invalid passkey credential")
+	ErrPasskeyExists            = errors.New("passkey already registered")
+
+	ErrInvalidPasswordResetToken = errors.New("invalid or expired password reset token")
+	ErrInvalidMagicLink          = errors.New("invalid or expired magic link")
+
+	ErrTooManyLoginAttempts = errors.New("too many login attempts")
+	ErrAccountLocked        = errors.New("account is temporarily locked")
+
+	ErrRoleNotFound     = errors.New("role not found")
+	ErrRoleExists       = errors.New("role already exists")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrInvalidRoleName  = errors.New("role and permission names must not be empty")
+
 	Isemailverified = errors.New("Email не подтвержден")
 )
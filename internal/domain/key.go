@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// ключ подписи JWT, приватная часть хранится в базе зашифрованной
+type SigningKey struct {
+	Kid        string     `json:"kid"`
+	Algorithm  string     `json:"algorithm"`
+	PrivateKey []byte     `json:"-"`
+	PublicKey  []byte     `json:"public_key"`
+	IsActive   bool       `json:"is_active"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RetiredAt  *time.Time `json:"retired_at"`
+
+	// новый ключ сначала только проверяет подписи, подписывать им начнут с этого момента
+	ActivatesAt *time.Time `json:"activates_at"`
+}
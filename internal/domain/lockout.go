@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// вход временно запрещен: прогрессивная задержка после неудачных попыток или блокировка
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", e.Unwrap(), e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	if e.Locked {
+		return ErrAccountLocked
+	}
+	return ErrTooManyLoginAttempts
+}
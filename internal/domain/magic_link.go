@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/Iowel/app-auth-service/pkg/pb"
+)
+
+// одноразовая ссылка для входа без пароля; в базе только хеш кода
+type MagicLink struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Email     string    `json:"email"`
+	CodeHash  []byte    `json:"-"`
+	UserAgent string    `json:"user_agent"`
+	IsUsed    bool      `json:"is_used"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+type RequestMagicLinkParams struct {
+	AppID int64
+	Email string
+
+	// ставит задачу на отправку письма; вызывается только если юзверь с таким email есть
+	AfterFind func(user *pb.User) error
+}
+
+type ConsumeMagicLinkParams struct {
+	LinkID    int64  `json:"link_id"`
+	Code      string `json:"code"`
+	UserAgent string `json:"user_agent"`
+	ClientIP  string `json:"client_ip"`
+}
+
+type ConsumeMagicLinkTxParams struct {
+	LinkID    int64
+	CodeHash  []byte
+	UserAgent string
+}
@@ -0,0 +1,105 @@
+package domain
+
+import "time"
+
+// приложение, которому разрешено входить через наш сервис
+type OAuthClient struct {
+	ClientID      string    `json:"client_id"`
+	SecretHash    []byte    `json:"-"`
+	Name          string    `json:"name"`
+	RedirectURIs  []string  `json:"redirect_uris"`
+	AllowedScopes []string  `json:"allowed_scopes"`
+	IsFirstParty  bool      `json:"is_first_party"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// машинный клиент: получает токены по client_credentials от своего имени
+	IsService       bool       `json:"is_service"`
+	SecretRotatedAt *time.Time `json:"secret_rotated_at,omitempty"`
+}
+
+// публичный клиент не хранит секрет и не может его предъявить
+func (c *OAuthClient) IsPublic() bool {
+	return len(c.SecretHash) == 0
+}
+
+// параметры запроса на /oauth/authorize
+type AuthorizeRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+}
+
+type AuthorizationCode struct {
+	ID                  int64     `json:"id"`
+	Hash                []byte    `json:"-"`
+	ClientID            string    `json:"client_id"`
+	UserID              int64     `json:"user_id"`
+	RedirectURI         string    `json:"redirect_uri"`
+	Scope               string    `json:"scope"`
+	CodeChallenge       string    `json:"code_challenge"`
+	CodeChallengeMethod string    `json:"code_challenge_method"`
+	Nonce               string    `json:"nonce"`
+	CreatedAt           time.Time `json:"created_at"`
+	ExpiredAt           time.Time `json:"expired_at"`
+}
+
+// параметры обмена кода на токены (grant_type=authorization_code)
+type CodeExchangeParams struct {
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	UserAgent    string
+	ClientIP     string
+}
+
+// параметры выдачи новой сессии с парой токенов
+type IssueTokensParams struct {
+	Scope     string
+	ClientID  string
+	UserAgent string
+	ClientIP  string
+}
+
+// ответ /oauth/token (RFC 6749, 5.1)
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+}
+
+// ответ /userinfo и claims ID токена (OpenID Connect Core, 5.1)
+type UserInfo struct {
+	Sub           string `json:"sub"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+}
+
+// по префиксу AuthorizeUser отличает токен сервиса (client_credentials) от токена юзверя
+const ServiceTokenPrefix = "svc_"
+
+// сервис, от имени которого выдан токен client_credentials
+type ServicePrincipal struct {
+	ClientID string   `json:"client_id"`
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+}
+
+type RegisterServiceClientParams struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// секрет в открытом виде есть только здесь, в базе лежит хеш
+type ServiceClientCredentials struct {
+	Client       *OAuthClient
+	ClientSecret string
+}
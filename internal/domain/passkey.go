@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// passkey юзверя (WebAuthn credential record)
+type Passkey struct {
+	ID              int64      `json:"id"`
+	UserID          int64      `json:"user_id"`
+	CredentialID    []byte     `json:"-"`
+	PublicKey       []byte     `json:"-"`
+	AttestationType string     `json:"-"`
+	Transports      []string   `json:"-"`
+	Flags           uint8      `json:"-"`
+	AAGUID          []byte     `json:"-"`
+	SignCount       uint32     `json:"-"`
+	Name            string     `json:"name"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+}
+
+const (
+	PasskeyCeremonyRegistration = "registration"
+	PasskeyCeremonyLogin        = "login"
+)
+
+// церемония между Begin* и Finish*; SessionData - webauthn.SessionData в json
+type PasskeyCeremony struct {
+	ID          int64     `json:"id"`
+	Hash        []byte    `json:"-"`
+	UserID      *int64    `json:"user_id"`
+	Kind        string    `json:"kind"`
+	SessionData []byte    `json:"-"`
+	IsUsed      bool      `json:"is_used"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiredAt   time.Time `json:"expired_at"`
+}
+
+// что отдаем браузеру на Begin*: options передаются в navigator.credentials.create/get
+type PasskeyOptions struct {
+	SessionToken string `json:"session_token"`
+	Options      []byte `json:"options"`
+}
+
+type FinishPasskeyRegistrationParams struct {
+	SessionToken string `json:"session_token"`
+	Credential   []byte `json:"credential"`
+	Name         string `json:"name"`
+}
+
+type FinishPasskeyLoginParams struct {
+	SessionToken string `json:"session_token"`
+	Credential   []byte `json:"credential"`
+	UserAgent    string `json:"user_agent"`
+	ClientIP     string `json:"client_ip"`
+}
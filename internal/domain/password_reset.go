@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/Iowel/app-auth-service/pkg/pb"
+)
+
+// одноразовый токен сброса пароля; Hash - sha256 токена из письма
+type PasswordReset struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Hash      []byte    `json:"-"`
+	IsUsed    bool      `json:"is_used"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+type RequestPasswordResetParams struct {
+	AppID int64
+	Email string
+
+	// ставит задачу на отправку письма; вызывается только если юзверь с таким email есть
+	AfterFind func(user *pb.User) error
+}
+
+type ResetPasswordParams struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// по префиксу AuthorizeUser отличает personal access token от токена сессии
+const PersonalAccessTokenPrefix = "pat_"
+
+// именованный токен для автоматизации и CI, выпускается самим юзвером
+type PersonalAccessToken struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreatePersonalAccessTokenParams struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// 0 - бессрочный
+	TTL time.Duration `json:"ttl"`
+}
+
+// токен в открытом виде есть только здесь, в базе лежит хеш
+type CreatedPersonalAccessToken struct {
+	Token     *PersonalAccessToken
+	PlainText string
+}
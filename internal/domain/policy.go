@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// ресурс-юзверь: в политику попадают его поля и профиль
+const ResourceTypeUser = "user"
+
+// CEL политика, см. internal/pkg/policy
+type Policy struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Action      string    `json:"action"`
+	Effect      string    `json:"effect"`
+	Expression  string    `json:"expression"`
+	Priority    int       `json:"priority"`
+	IsEnabled   bool      `json:"is_enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type AuthorizeParams struct {
+	SubjectID    int64          `json:"subject_id"`
+	Action       string         `json:"action"`
+	ResourceType string         `json:"resource_type"`
+	ResourceID   string         `json:"resource_id"`
+	Attributes   map[string]any `json:"attributes"`
+}
+
+type AuthorizeDecision struct {
+	Allowed bool   `json:"allowed"`
+	Policy  string `json:"policy"`
+	Effect  string `json:"effect"`
+	Reason  string `json:"reason"`
+}
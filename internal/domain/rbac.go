@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// право, которое дает все остальные
+const PermissionAll = "*"
+
+// права, на которые завязаны наши собственные ручки
+const (
+	PermissionRolesManage    = "roles.manage"
+	PermissionKeysRotate     = "keys.rotate"
+	PermissionAccountsUnlock = "accounts.unlock"
+	// Authorize от имени другого юзверя - для сервисов, которые проверяют доступ своих пользователей
+	PermissionPoliciesEvaluate = "policies.evaluate"
+	PermissionClientsManage    = "clients.manage"
+)
+
+// scope personal access token'а для чтения своего аккаунта; для админских ручек scope совпадает с правом
+const ScopeAccountRead = "account.read"
+
+type Role struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// роли юзверя и права, собранные со всех его ролей; это и лежит в кэше
+type UserPermissions struct {
+	UserID      int64    `json:"user_id"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+func (p *UserPermissions) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+func (p *UserPermissions) HasPermission(permission string) bool {
+	return slices.Contains(p.Permissions, PermissionAll) || slices.Contains(p.Permissions, permission)
+}
+
+type CreateRoleParams struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
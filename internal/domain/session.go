@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// сессия - один залогиненный девайс юзверя
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     int64     `json:"user_id"`
+	UserAgent  string    `json:"user_agent"`
+	ClientIP   string    `json:"client_ip"`
+	ClientID   string    `json:"client_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type LoginParams struct {
+	AppID     int64  `json:"app_id"`
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	UserAgent string `json:"user_agent"`
+	ClientIP  string `json:"client_ip"`
+}
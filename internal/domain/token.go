@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/Iowel/app-auth-service/pkg/pb"
+)
 
 type Token struct {
 	PlainText string    `json:"token"`
@@ -9,3 +13,53 @@ type Token struct {
 	Expiry    time.Time `json:"expiry"`
 	Scope     string    `json:"-"`
 }
+
+type RefreshToken struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	FamilyID  string    `json:"family_id"`
+	SessionID string    `json:"session_id"`
+	ClientID  string    `json:"client_id"`
+	Scope     string    `json:"scope"`
+	Hash      []byte    `json:"-"`
+	Expiry    time.Time `json:"expiry"`
+	IsUsed    bool      `json:"is_used"`
+	IsRevoked bool      `json:"is_revoked"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// пара токенов, которую получает клиент после логина
+type AuthTokens struct {
+	AccessToken  *pb.Token
+	RefreshToken *pb.Token
+}
+
+// информация о выданном access токене, по ней отвечаем на introspection
+type TokenInfo struct {
+	User      *pb.User
+	Scope     string
+	SessionID string
+	ClientID  string
+	IssuedAt  time.Time
+	Expiry    time.Time
+
+	// права токена: для personal access token - выданные юзвером scopes, для остальных - Scope
+	Scopes []string
+
+	// у токенов client_credentials юзверя нет, вместо него - сервис
+	Service *ServicePrincipal
+}
+
+// ответ на token introspection (RFC 7662)
+type TokenIntrospection struct {
+	Active    bool   `json:"active"`
+	Sub       string `json:"sub,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Role      string `json:"role,omitempty"`
+	AppID     int64  `json:"app_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// TOTP секрет юзверя; Secret хранится зашифрованным
+type UserTOTP struct {
+	UserID       int64      `json:"user_id"`
+	Secret       []byte     `json:"-"`
+	IsConfirmed  bool       `json:"is_confirmed"`
+	LastUsedStep int64      `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ConfirmedAt  *time.Time `json:"confirmed_at"`
+}
+
+// вход по паролю прошел, ждем второй фактор
+type TwoFactorChallenge struct {
+	ID        int64     `json:"id"`
+	Hash      []byte    `json:"-"`
+	UserID    int64     `json:"user_id"`
+	UserAgent string    `json:"user_agent"`
+	ClientIP  string    `json:"client_ip"`
+	Attempts  int       `json:"attempts"`
+	IsUsed    bool      `json:"is_used"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type VerifyTwoFactorParams struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+// результат логина: либо токены, либо challenge для второго фактора
+type LoginResult struct {
+	Tokens         *AuthTokens
+	ChallengeToken string
+}
@@ -9,7 +9,6 @@ import (
 type User struct {
 	ID              int       `json:"id"`
 	Email           string    `json:"email"`
-	Password        string    `json:"password"`
 	Name            string    `json:"name"`
 	IsEmailVerified bool      `json:"is_email_verified"`
 	Avatar          string    `json:"avatar"`
@@ -19,17 +18,35 @@ type User struct {
 }
 
 type UpdateUserParams struct {
-	Name            string    `json:"username"`
-	Password        string    `json:"password"`
-	Email           string    `json:"email"`
-	IsEmailVerified bool      `json:"is_email_verified"`
-	ID              int64     `json:"id"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              int64   `json:"id"`
+	Name            *string `json:"username"`
+	Password        *string `json:"password"`
+	Email           *string `json:"email"`
+	IsEmailVerified *bool   `json:"is_email_verified"`
+}
+
+type UpdateUserTxParams struct {
+	UpdateUserParams
+
+	// проверяется при смене email или пароля
+	CurrentPassword string
+	// при смене пароля остается только эта сессия, остальные девайсы разлогиниваем
+	KeepSessionID string
+
+	// вызывается внутри транзакции, если у юзверя поменялся email
+	AfterEmailChange func(*pb.User) error
+}
+
+type UpdateUserTxResult struct {
+	User *pb.User
 }
 
 type CreateUserTxParams struct {
-	User        *pb.User
-	AfterCreate func(*pb.User) error
+	User *pb.User
+	// пароль из запроса; сервис хеширует его в PasswordHash и затирает, в базу попадает только хеш
+	Password     string
+	PasswordHash string
+	AfterCreate  func(*pb.User) error
 
 	// дополнительные данные или флаги, которые могут понадобиться
 	// в процессе обработки. можно использовать для логирования, валидации и прочего
@@ -0,0 +1,32 @@
+package jwtauth
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// claims ID токена OpenID Connect, которые подписываем теми же ключами, что и access токены
+type IDTokenClaims struct {
+	Nonce           string `json:"nonce,omitempty"`
+	AccessTokenHash string `json:"at_hash,omitempty"`
+	Email           string `json:"email,omitempty"`
+	EmailVerified   *bool  `json:"email_verified,omitempty"`
+	Name            string `json:"name,omitempty"`
+	jwt.RegisteredClaims
+}
+
+func (m *Manager) SignIDToken(claims *IDTokenClaims) (string, error) {
+	if claims.Issuer == "" {
+		claims.Issuer = m.issuer
+	}
+
+	return m.sign(claims)
+}
+
+// at_hash: левая половина хеша access токена; для Ed25519 хеш - SHA-512
+func AccessTokenHash(accessToken string) string {
+	sum := sha512.Sum512([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+}
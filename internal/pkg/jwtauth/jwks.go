@@ -0,0 +1,42 @@
+package jwtauth
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// JSON Web Key Set (RFC 7517) с публичными ключами для проверки подписи
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func publicJWK(kid string, publicKey ed25519.PublicKey) JWK {
+	return JWK{
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(publicKey),
+		Kid: kid,
+		Use: "sig",
+		Alg: "EdDSA",
+	}
+}
+
+// отпечаток ключа по RFC 7638, используем его как kid
+func Thumbprint(publicKey ed25519.PublicKey) string {
+	x := base64.RawURLEncoding.EncodeToString(publicKey)
+	canonical := `{"crv":"Ed25519","kty":"OKP","x":"` + x + `"}`
+
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,193 @@
+package jwtauth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Подписываем access токены асимметричным ключом (EdDSA),
+// чтобы другие сервисы могли проверять их сами по публичному ключу из JWKS
+
+const Algorithm = "EdDSA"
+
+var (
+	ErrInvalidToken = errors.New("invalid jwt")
+	ErrNoActiveKey  = errors.New("no active signing key")
+)
+
+type Claims struct {
+	Role      string `json:"role,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	SessionID string `json:"sid,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	AppID     int64  `json:"app_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ключ подписи; у выведенных из оборота ключей приватной части может не быть
+type Key struct {
+	Kid        string
+	PrivateKey ed25519.PrivateKey
+	PublicKey  ed25519.PublicKey
+	Active     bool
+}
+
+type Manager struct {
+	issuer string
+
+	mu         sync.RWMutex
+	active     *Key
+	publicKeys map[string]ed25519.PublicKey
+	kids       []string
+}
+
+func NewManager(issuer string) *Manager {
+	return &Manager{
+		issuer:     issuer,
+		publicKeys: make(map[string]ed25519.PublicKey),
+	}
+}
+
+func GenerateKey() (*Key, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	return &Key{
+		Kid:        Thumbprint(publicKey),
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}, nil
+}
+
+// подписываем активным ключом, проверяем любым из переданных
+func (m *Manager) SetKeys(keys []*Key) error {
+	var active *Key
+	publicKeys := make(map[string]ed25519.PublicKey, len(keys))
+	kids := make([]string, 0, len(keys))
+
+	for _, k := range keys {
+		if k.Active {
+			if active != nil {
+				return errors.New("more than one active signing key")
+			}
+			if k.PrivateKey == nil {
+				return errors.New("active signing key without private key")
+			}
+			active = k
+		}
+		publicKeys[k.Kid] = k.PublicKey
+		kids = append(kids, k.Kid)
+	}
+
+	if active == nil {
+		return ErrNoActiveKey
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.active = active
+	m.publicKeys = publicKeys
+	m.kids = kids
+
+	return nil
+}
+
+func (m *Manager) Sign(claims *Claims) (string, error) {
+	if claims.Issuer == "" {
+		claims.Issuer = m.issuer
+	}
+
+	return m.sign(claims)
+}
+
+func (m *Manager) sign(claims jwt.Claims) (string, error) {
+	m.mu.RLock()
+	active := m.active
+	m.mu.RUnlock()
+
+	if active == nil {
+		return "", ErrNoActiveKey
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = active.Kid
+
+	signed, err := token.SignedString(active.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign jwt: %w", err)
+	}
+
+	return signed, nil
+}
+
+func (m *Manager) Verify(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		m.mu.RLock()
+		publicKey, ok := m.publicKeys[kid]
+		m.mu.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+		return publicKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	return claims, nil
+}
+
+func (m *Manager) JWKS() *JWKSet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	jwks := &JWKSet{Keys: make([]JWK, 0, len(m.kids))}
+	for _, kid := range m.kids {
+		jwks.Keys = append(jwks.Keys, publicJWK(kid, m.publicKeys[kid]))
+	}
+
+	return jwks
+}
+
+func (m *Manager) Issuer() string {
+	return m.issuer
+}
+
+// похоже ли значение на JWT (header.payload.signature), а не на непрозрачный токен
+func LooksLikeJWT(token string) bool {
+	dots := 0
+	for _, c := range token {
+		if c == '.' {
+			dots++
+		}
+	}
+	return dots == 2
+}
+
+func NewClaims(subject string, ttl time.Duration) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		Subject:   subject,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+}
@@ -0,0 +1,232 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+)
+
+// Политики - CEL выражения над subject, resource, attributes и action.
+// Решает deny-overrides: любая сработавшая deny политика запрещает, иначе нужна хотя бы одна allow
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+
+	// политика с таким action применяется к любому действию
+	AnyAction = "*"
+
+	// чтобы кривое выражение не съело CPU на каждом запросе
+	costLimit = 100000
+)
+
+var ErrInvalidPolicy = errors.New("invalid policy")
+
+type Rule struct {
+	Name       string
+	Action     string
+	Effect     string
+	Expression string
+	Priority   int
+}
+
+// то, что видят выражения
+type Input struct {
+	Subject    map[string]any
+	Resource   map[string]any
+	Attributes map[string]any
+	Action     string
+}
+
+type Decision struct {
+	Allowed bool
+	// имя политики, которая решила; пусто - ни одна не подошла
+	Policy string
+	Effect string
+	Reason string
+}
+
+type compiledRule struct {
+	Rule
+	program cel.Program
+}
+
+type Engine struct {
+	env *cel.Env
+
+	mu    sync.RWMutex
+	rules []*compiledRule
+}
+
+func NewEngine() (*Engine, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("subject", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("resource", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("attributes", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("action", cel.StringType),
+		cel.Variable("now", cel.TimestampType),
+		// числа из JSON атрибутов приходят double, а поля юзверя - int
+		cel.CrossTypeNumericComparisons(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cel env: %w", err)
+	}
+
+	return &Engine{env: env}, nil
+}
+
+// подменяем все политики разом; не скомпилировавшиеся пропускаем, чтобы одна ошибка не выключила остальные
+func (e *Engine) SetRules(rules []Rule) []error {
+	compiled := make([]*compiledRule, 0, len(rules))
+	var errs []error
+
+	for _, rule := range rules {
+		c, err := e.compile(rule)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+
+	// сначала более приоритетные, при равенстве - по имени, чтобы порядок не зависел от базы
+	sort.SliceStable(compiled, func(i, j int) bool {
+		if compiled[i].Priority != compiled[j].Priority {
+			return compiled[i].Priority > compiled[j].Priority
+		}
+		return compiled[i].Name < compiled[j].Name
+	})
+
+	e.mu.Lock()
+	e.rules = compiled
+	e.mu.Unlock()
+
+	return errs
+}
+
+func (e *Engine) Evaluate(input Input) Decision {
+	e.mu.RLock()
+	rules := e.rules
+	e.mu.RUnlock()
+
+	vars := map[string]any{
+		"subject":    nonNil(input.Subject),
+		"resource":   nonNil(input.Resource),
+		"attributes": nonNil(input.Attributes),
+		"action":     input.Action,
+		"now":        time.Now(),
+	}
+
+	var allow *compiledRule
+
+	for _, rule := range rules {
+		if !matchAction(rule.Action, input.Action) {
+			continue
+		}
+		// разрешение уже есть, дальше интересны только запреты
+		if allow != nil && rule.Effect == EffectAllow {
+			continue
+		}
+
+		matched, err := rule.eval(vars)
+		if err != nil {
+			// не смогли проверить запрет - значит запрещаем
+			if rule.Effect == EffectDeny {
+				return Decision{
+					Policy: rule.Name,
+					Effect: EffectDeny,
+					Reason: fmt.Sprintf("policy %q failed to evaluate: %s", rule.Name, err),
+				}
+			}
+			log.Printf("policy %q failed to evaluate: %s", rule.Name, err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+
+		if rule.Effect == EffectDeny {
+			return Decision{
+				Policy: rule.Name,
+				Effect: EffectDeny,
+				Reason: fmt.Sprintf("denied by policy %q", rule.Name),
+			}
+		}
+		if allow == nil {
+			allow = rule
+		}
+	}
+
+	if allow == nil {
+		return Decision{Reason: fmt.Sprintf("no policy allows action %q", input.Action)}
+	}
+
+	return Decision{
+		Allowed: true,
+		Policy:  allow.Name,
+		Effect:  EffectAllow,
+		Reason:  fmt.Sprintf("allowed by policy %q", allow.Name),
+	}
+}
+
+func (e *Engine) compile(rule Rule) (*compiledRule, error) {
+	if rule.Effect != EffectAllow && rule.Effect != EffectDeny {
+		return nil, fmt.Errorf("%w: %s: effect must be %q or %q", ErrInvalidPolicy, rule.Name, EffectAllow, EffectDeny)
+	}
+	if rule.Action == "" {
+		return nil, fmt.Errorf("%w: %s: empty action", ErrInvalidPolicy, rule.Name)
+	}
+
+	ast, iss := e.env.Compile(rule.Expression)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidPolicy, rule.Name, iss.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("%w: %s: expression must return bool, got %s", ErrInvalidPolicy, rule.Name, ast.OutputType())
+	}
+
+	program, err := e.env.Program(ast, cel.CostLimit(costLimit))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidPolicy, rule.Name, err)
+	}
+
+	return &compiledRule{Rule: rule, program: program}, nil
+}
+
+func (r *compiledRule) eval(vars map[string]any) (bool, error) {
+	out, _, err := r.program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression returned %s, not bool", out.Type())
+	}
+
+	return matched, nil
+}
+
+// "wallet.*" подходит для "wallet.withdraw" и любых других действий с кошельком
+func matchAction(pattern, action string) bool {
+	if pattern == AnyAction || pattern == action {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(action, prefix)
+	}
+	return false
+}
+
+func nonNil(m map[string]any) map[string]any {
+	if m == nil {
+		return map[string]any{}
+	}
+	return m
+}
@@ -0,0 +1,149 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestEngine(t *testing.T, rules ...Rule) *Engine {
+	t.Helper()
+
+	engine, err := NewEngine()
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	if errs := engine.SetRules(rules); len(errs) != 0 {
+		t.Fatalf("SetRules: %v", errs)
+	}
+	return engine
+}
+
+func TestEvaluateDenyOverrides(t *testing.T) {
+	engine := newTestEngine(t,
+		Rule{Name: "owner-can-edit", Action: "profile.edit", Effect: EffectAllow, Expression: `subject.id == resource.owner_id`, Priority: 100},
+		Rule{Name: "admins-can-edit", Action: "profile.*", Effect: EffectAllow, Expression: `"admin" in subject.roles`},
+		// низкий приоритет не мешает запрету победить разрешения
+		Rule{Name: "banned-cannot-act", Action: AnyAction, Effect: EffectDeny, Expression: `subject.status == "banned"`, Priority: -10},
+	)
+
+	tests := []struct {
+		name    string
+		input   Input
+		allowed bool
+		policy  string
+	}{
+		{
+			name:    "owner",
+			input:   Input{Action: "profile.edit", Subject: map[string]any{"id": 1, "status": "active", "roles": []string{}}, Resource: map[string]any{"owner_id": 1}},
+			allowed: true,
+			policy:  "owner-can-edit",
+		},
+		{
+			name:    "admin by action prefix",
+			input:   Input{Action: "profile.delete", Subject: map[string]any{"id": 2, "status": "active", "roles": []string{"admin"}}, Resource: map[string]any{"owner_id": 1}},
+			allowed: true,
+			policy:  "admins-can-edit",
+		},
+		{
+			name:   "banned owner",
+			input:  Input{Action: "profile.edit", Subject: map[string]any{"id": 1, "status": "banned", "roles": []string{"admin"}}, Resource: map[string]any{"owner_id": 1}},
+			policy: "banned-cannot-act",
+		},
+		{
+			name:  "nobody allows",
+			input: Input{Action: "profile.edit", Subject: map[string]any{"id": 3, "status": "active", "roles": []string{}}, Resource: map[string]any{"owner_id": 1}},
+		},
+		{
+			name:  "other action",
+			input: Input{Action: "wallet.withdraw", Subject: map[string]any{"id": 1, "status": "active", "roles": []string{"admin"}}, Resource: map[string]any{"owner_id": 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := engine.Evaluate(tt.input)
+			if decision.Allowed != tt.allowed || decision.Policy != tt.policy {
+				t.Errorf("Evaluate() = %+v, want allowed %v by %q", decision, tt.allowed, tt.policy)
+			}
+		})
+	}
+}
+
+// не смогли вычислить запрет - запрещаем; сломанное разрешение просто пропускаем
+func TestEvaluateErrors(t *testing.T) {
+	engine := newTestEngine(t,
+		Rule{Name: "allow-all", Action: AnyAction, Effect: EffectAllow, Expression: `true`},
+		Rule{Name: "broken-allow", Action: "a", Effect: EffectAllow, Expression: `subject.missing == 1`, Priority: 10},
+		Rule{Name: "broken-deny", Action: "b", Effect: EffectDeny, Expression: `subject.missing == 1`},
+	)
+
+	if decision := engine.Evaluate(Input{Action: "a"}); !decision.Allowed || decision.Policy != "allow-all" {
+		t.Errorf("broken allow: Evaluate() = %+v, want allowed by allow-all", decision)
+	}
+	if decision := engine.Evaluate(Input{Action: "b"}); decision.Allowed || decision.Policy != "broken-deny" {
+		t.Errorf("broken deny: Evaluate() = %+v, want denied by broken-deny", decision)
+	}
+}
+
+// числа из JSON атрибутов - double, поля юзверя - int; сравнение между ними должно работать
+func TestEvaluateCrossTypeNumbers(t *testing.T) {
+	engine := newTestEngine(t,
+		Rule{Name: "level", Action: AnyAction, Effect: EffectAllow, Expression: `attributes.subject_status_level > subject.level`},
+	)
+
+	decision := engine.Evaluate(Input{
+		Action:     "profile.edit",
+		Subject:    map[string]any{"level": int64(1)},
+		Attributes: map[string]any{"subject_status_level": float64(3)},
+	})
+	if !decision.Allowed {
+		t.Errorf("Evaluate() = %+v, want allowed", decision)
+	}
+}
+
+func TestSetRulesSkipsInvalid(t *testing.T) {
+	engine, err := NewEngine()
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+
+	errs := engine.SetRules([]Rule{
+		{Name: "ok", Action: AnyAction, Effect: EffectAllow, Expression: `true`},
+		{Name: "bad-effect", Action: AnyAction, Effect: "maybe", Expression: `true`},
+		{Name: "no-action", Effect: EffectAllow, Expression: `true`},
+		{Name: "syntax", Action: AnyAction, Effect: EffectAllow, Expression: `subject.id ==`},
+		{Name: "not-bool", Action: AnyAction, Effect: EffectAllow, Expression: `"yes"`},
+	})
+	if len(errs) != 4 {
+		t.Fatalf("SetRules returned %d errors, want 4: %v", len(errs), errs)
+	}
+	for _, err := range errs {
+		if !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("error %v is not ErrInvalidPolicy", err)
+		}
+	}
+
+	// одна кривая политика не выключает остальные
+	if decision := engine.Evaluate(Input{Action: "anything"}); !decision.Allowed {
+		t.Errorf("Evaluate() = %+v, want allowed by the valid rule", decision)
+	}
+}
+
+func TestMatchAction(t *testing.T) {
+	tests := []struct {
+		pattern, action string
+		want            bool
+	}{
+		{"*", "wallet.withdraw", true},
+		{"wallet.withdraw", "wallet.withdraw", true},
+		{"wallet.*", "wallet.withdraw", true},
+		{"wallet.*", "wallets.list", false},
+		{"wallet.withdraw", "wallet.deposit", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchAction(tt.pattern, tt.action); got != tt.want {
+			t.Errorf("matchAction(%q, %q) = %v, want %v", tt.pattern, tt.action, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238, приложение B: SHA1, секрет "12345678901234567890"; у нас 6 цифр - последние 6 из 8
+func TestValidateRFC6238Vectors(t *testing.T) {
+	secret := encoding.EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		now := time.Unix(tt.unix, 0)
+
+		step, ok := Validate(secret, tt.code, now)
+		if !ok {
+			t.Errorf("Validate(%d, %s) = false, want true", tt.unix, tt.code)
+			continue
+		}
+		if want := tt.unix / 30; step != want {
+			t.Errorf("Validate(%d, %s) step = %d, want %d", tt.unix, tt.code, step, want)
+		}
+	}
+}
+
+// часы телефона могут отставать или спешить на один шаг, но не больше
+func TestValidateSkew(t *testing.T) {
+	secret := encoding.EncodeToString([]byte("12345678901234567890"))
+	const code = "081804" // шаг 1111111109 / 30
+
+	codeTime := time.Unix(1111111109, 0)
+
+	for _, shift := range []time.Duration{-Period, Period} {
+		if _, ok := Validate(secret, code, codeTime.Add(shift)); !ok {
+			t.Errorf("code rejected with clock shift %s", shift)
+		}
+	}
+	for _, shift := range []time.Duration{-2 * Period, 2 * Period} {
+		if _, ok := Validate(secret, code, codeTime.Add(shift)); ok {
+			t.Errorf("code accepted with clock shift %s", shift)
+		}
+	}
+}
+
+func TestValidateMalformed(t *testing.T) {
+	secret := encoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(59, 0)
+
+	for _, code := range []string{"", "28708", "2870820", "94287082"} {
+		if _, ok := Validate(secret, code, now); ok {
+			t.Errorf("Validate accepted code %q", code)
+		}
+	}
+	if _, ok := Validate("not base32!", "287082", now); ok {
+		t.Error("Validate accepted invalid secret")
+	}
+}
+
+func TestGenerateSecretRoundTrip(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+
+	key, err := encoding.DecodeString(secret)
+	if err != nil || len(key) != secretSize {
+		t.Fatalf("secret %q decodes to %d bytes, err %v", secret, len(key), err)
+	}
+
+	now := time.Now()
+	code := generate(key, now.Unix()/int64(Period.Seconds()))
+	if _, ok := Validate(secret, code, now); !ok {
+		t.Errorf("freshly generated code %s rejected", code)
+	}
+}
@@ -0,0 +1,141 @@
+package validator
+
+import (
+	"fmt"
+
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+)
+
+// проверка запроса до сервисного слоя; сообщения без полей и неизвестные считаем валидными.
+// v2 использует те же сообщения запросов, так что правила общие
+func Request(req interface{}) Violations {
+	var v Violations
+
+	switch r := req.(type) {
+
+	// регистрация и вход
+	case *pb.RegisterUserRequest:
+		v.Add("name", ValidateName(r.GetName()))
+		v.Add("email", ValidateEmail(r.GetEmail()))
+		v.Add("password", ValidatePassword(r.GetPassword()))
+		v.Add("app_id", ValidateID(r.GetAppId()))
+	case *pb.LoginUserRequest:
+		v.Add("email", ValidateLoginEmail(r.GetEmail()))
+		v.Add("password", ValidateLoginPassword(r.GetPassword()))
+		v.Add("app_id", ValidateID(r.GetAppId()))
+	case *pb.RefreshTokenRequest:
+		v.Add("refresh_token", ValidateRequired(r.GetRefreshToken()))
+	case *pb.VerifyEmailRequest:
+		v.Add("email_id", ValidateID(r.GetEmailId()))
+		v.Add("secret_code", ValidateRequired(r.GetSecretCode()))
+	case *pb.VerifyTokenRequest:
+		v.Add("app_id", ValidateOptionalID(r.GetAppId()))
+	case *pb.VerifyRoleRequest:
+		v.Add("role", ValidateOptional(r.GetRole()))
+		v.Add("permission", ValidateOptional(r.GetPermission()))
+
+	// профиль: пустое поле - не меняем, заданное проверяем как при регистрации
+	case *pb.UpdateUserRequest:
+		if r.Name != nil {
+			v.Add("name", ValidateName(r.GetName()))
+		}
+		if r.Email != nil {
+			v.Add("email", ValidateEmail(r.GetEmail()))
+		}
+		if r.Password != nil {
+			v.Add("password", ValidatePassword(r.GetPassword()))
+		}
+		v.Add("current_password", ValidateOptional(r.GetCurrentPassword()))
+
+	// восстановление доступа
+	case *pb.RequestPasswordResetRequest:
+		v.Add("email", ValidateLoginEmail(r.GetEmail()))
+		v.Add("app_id", ValidateOptionalID(r.GetAppId()))
+	case *pb.ResetPasswordRequest:
+		v.Add("token", ValidateRequired(r.GetToken()))
+		v.Add("new_password", ValidatePassword(r.GetNewPassword()))
+	case *pb.RequestMagicLinkRequest:
+		v.Add("email", ValidateLoginEmail(r.GetEmail()))
+		v.Add("app_id", ValidateOptionalID(r.GetAppId()))
+	case *pb.ConsumeMagicLinkRequest:
+		v.Add("link_id", ValidateID(r.GetLinkId()))
+		v.Add("code", ValidateRequired(r.GetCode()))
+	case *pb.UnlockAccountRequest:
+		v.Add("email", ValidateLoginEmail(r.GetEmail()))
+		v.Add("app_id", ValidateOptionalID(r.GetAppId()))
+
+	// сессии и 2FA
+	case *pb.RevokeSessionRequest:
+		v.Add("session_id", ValidateRequired(r.GetSessionId()))
+	case *pb.ConfirmTwoFactorRequest:
+		v.Add("code", ValidateRequired(r.GetCode()))
+	case *pb.VerifyTwoFactorRequest:
+		v.Add("challenge_token", ValidateRequired(r.GetChallengeToken()))
+		v.Add("code", ValidateRequired(r.GetCode()))
+	case *pb.DisableTwoFactorRequest:
+		v.Add("code", ValidateRequired(r.GetCode()))
+
+	// passkeys: credential - JSON от браузера, его разбирает webauthn
+	case *pb.FinishPasskeyRegistrationRequest:
+		v.Add("session_token", ValidateRequired(r.GetSessionToken()))
+		v.Add("name", ValidateOptional(r.GetName()))
+		v.Add("credential", ValidateNotEmpty(r.GetCredential()))
+	case *pb.FinishPasskeyLoginRequest:
+		v.Add("session_token", ValidateRequired(r.GetSessionToken()))
+		v.Add("credential", ValidateNotEmpty(r.GetCredential()))
+
+	// personal access tokens
+	case *pb.CreatePersonalAccessTokenRequest:
+		v.Add("name", ValidateRequired(r.GetName()))
+		v.addEach("scopes", r.GetScopes(), true)
+		v.Add("expires_in_seconds", ValidateOptionalID(r.GetExpiresInSeconds()))
+	case *pb.RevokePersonalAccessTokenRequest:
+		v.Add("id", ValidateID(r.GetId()))
+
+	// RBAC и политики
+	case *pb.CreateRoleRequest:
+		v.Add("name", ValidateRequired(r.GetName()))
+		v.Add("description", ValidateOptional(r.GetDescription()))
+		v.addEach("permissions", r.GetPermissions(), false)
+	case *pb.DeleteRoleRequest:
+		v.Add("name", ValidateRequired(r.GetName()))
+	case *pb.GrantRolePermissionRequest:
+		v.Add("role", ValidateRequired(r.GetRole()))
+		v.Add("permission", ValidateRequired(r.GetPermission()))
+	case *pb.RevokeRolePermissionRequest:
+		v.Add("role", ValidateRequired(r.GetRole()))
+		v.Add("permission", ValidateRequired(r.GetPermission()))
+	case *pb.AssignUserRoleRequest:
+		v.Add("user_id", ValidateID(r.GetUserId()))
+		v.Add("role", ValidateRequired(r.GetRole()))
+	case *pb.RevokeUserRoleRequest:
+		v.Add("user_id", ValidateID(r.GetUserId()))
+		v.Add("role", ValidateRequired(r.GetRole()))
+	case *pb.GetUserPermissionsRequest:
+		v.Add("user_id", ValidateID(r.GetUserId()))
+	case *pb.AuthorizeRequest:
+		v.Add("subject_id", ValidateOptionalID(r.GetSubjectId()))
+		v.Add("action", ValidateRequired(r.GetAction()))
+		v.Add("resource.type", ValidateOptional(r.GetResource().GetType()))
+		v.Add("resource.id", ValidateOptional(r.GetResource().GetId()))
+
+	// сервисные клиенты
+	case *pb.RegisterServiceClientRequest:
+		v.Add("name", ValidateRequired(r.GetName()))
+		v.addEach("scopes", r.GetScopes(), true)
+	case *pb.RotateServiceClientSecretRequest:
+		v.Add("client_id", ValidateRequired(r.GetClientId()))
+	}
+
+	return v
+}
+
+// поле списка в нарушении - с индексом, как в google.rpc.BadRequest: "scopes[1]"
+func (v *Violations) addEach(field string, values []string, required bool) {
+	if required && len(values) == 0 {
+		v.Add(field, ValidateRequired(""))
+	}
+	for i, value := range values {
+		v.Add(fmt.Sprintf("%s[%d]", field, i), ValidateRequired(value))
+	}
+}
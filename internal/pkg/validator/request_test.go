@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+func fields(v Violations) []string {
+	result := make([]string, 0, len(v))
+	for _, violation := range v {
+		result = append(result, violation.Field)
+	}
+	return result
+}
+
+func TestRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		req  interface{}
+		want []string
+	}{
+		{
+			name: "valid registration",
+			req:  &pb.RegisterUserRequest{Name: "Иван Петров", Email: "ivan@example.com", Password: "secret123", AppId: 1},
+		},
+		{
+			name: "registration reports every field at once",
+			req:  &pb.RegisterUserRequest{Name: "x", Email: "Ivan <ivan@example.com>", Password: "short", AppId: 0},
+			want: []string{"name", "email", "password", "app_id"},
+		},
+		{
+			name: "password without digits",
+			req:  &pb.RegisterUserRequest{Name: "ivan", Email: "ivan@example.com", Password: "onlyletters", AppId: 1},
+			want: []string{"password"},
+		},
+		{
+			name: "password longer than bcrypt accepts",
+			req:  &pb.RegisterUserRequest{Name: "ivan", Email: "ivan@example.com", Password: strings.Repeat("a1", 37), AppId: 1},
+			want: []string{"password"},
+		},
+		{
+			// старые аккаунты заводились до валидации - при логине политику пароля и синтаксис email не проверяем
+			name: "login with legacy credentials",
+			req:  &pb.LoginUserRequest{Email: "legacy@localhost", Password: "123", AppId: 1},
+		},
+		{
+			name: "login without credentials",
+			req:  &pb.LoginUserRequest{AppId: 1},
+			want: []string{"email", "password"},
+		},
+		{
+			name: "update without changes",
+			req:  &pb.UpdateUserRequest{},
+		},
+		{
+			name: "update checks only provided fields",
+			req:  &pb.UpdateUserRequest{Email: ptr("not-an-email"), CurrentPassword: ptr("secret123")},
+			want: []string{"email"},
+		},
+		{
+			name: "verify token with default app",
+			req:  &pb.VerifyTokenRequest{AppId: 0},
+		},
+		{
+			name: "negative app id",
+			req:  &pb.VerifyTokenRequest{AppId: -1},
+			want: []string{"app_id"},
+		},
+		{
+			name: "list fields are reported with index",
+			req:  &pb.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{"deploy", " "}},
+			want: []string{"scopes[1]"},
+		},
+		{
+			name: "required list",
+			req:  &pb.RegisterServiceClientRequest{Name: "billing"},
+			want: []string{"scopes"},
+		},
+		{
+			name: "too long opaque value",
+			req:  &pb.RefreshTokenRequest{RefreshToken: strings.Repeat("a", maxValueLength+1)},
+			want: []string{"refresh_token"},
+		},
+		{
+			name: "webauthn json is not length limited",
+			req:  &pb.FinishPasskeyLoginRequest{SessionToken: "token", Credential: strings.Repeat("a", 4*maxValueLength)},
+		},
+		{
+			name: "nested field",
+			req:  &pb.AuthorizeRequest{Action: "profile.edit", Resource: &pb.AuthorizeResource{Type: strings.Repeat("a", maxValueLength+1)}},
+			want: []string{"resource.type"},
+		},
+		{
+			name: "unknown message is valid",
+			req:  &pb.ListSessionsRequest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fields(Request(tt.req))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Request() violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"Ivan", "Иван Петров", "john.doe_1", "Jean-Luc"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", "a", " Ivan", "Ivan ", "<script>", strings.Repeat("я", maxNameLength+1)} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) = nil, want error", name)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxEmailLength = 254
+	minNameLength  = 2
+	maxNameLength  = 64
+	minPasswordLen = 8
+	// bcrypt молча обрезает все, что длиннее 72 байт
+	maxPasswordLen = 72
+	// коды, токены, имена ролей и прочие непрозрачные строки
+	maxValueLength = 512
+)
+
+// нарушение одного поля запроса; Field - имя поля как в proto
+type Violation struct {
+	Field string
+	Err   error
+}
+
+// копим все нарушения запроса, чтобы клиент увидел их разом, а не по одному
+type Violations []Violation
+
+func (v *Violations) Add(field string, err error) {
+	if err != nil {
+		*v = append(*v, Violation{Field: field, Err: err})
+	}
+}
+
+func ValidateEmail(value string) error {
+	if value == "" {
+		return errors.New("is required")
+	}
+	if len(value) > maxEmailLength {
+		return fmt.Errorf("must be at most %d characters", maxEmailLength)
+	}
+
+	// ParseAddress пропускает "Имя <a@b.c>", нам нужен голый адрес
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return errors.New("is not a valid email address")
+	}
+
+	return nil
+}
+
+// для поиска уже заведенного аккаунта синтаксис не проверяем: до валидации могли зарегистрировать что угодно
+func ValidateLoginEmail(value string) error {
+	if value == "" {
+		return errors.New("is required")
+	}
+	if len(value) > maxEmailLength {
+		return fmt.Errorf("must be at most %d characters", maxEmailLength)
+	}
+	return nil
+}
+
+// буквы любого алфавита, цифры, пробел, точка, дефис и подчеркивание
+func ValidateName(value string) error {
+	length := utf8.RuneCountInString(value)
+	if length < minNameLength || length > maxNameLength {
+		return fmt.Errorf("must contain from %d to %d characters", minNameLength, maxNameLength)
+	}
+	if strings.TrimSpace(value) != value {
+		return errors.New("must not start or end with a space")
+	}
+
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" ._-", r) {
+			return errors.New("must contain only letters, digits, spaces, dots, dashes and underscores")
+		}
+	}
+
+	return nil
+}
+
+// политика для нового пароля: регистрация, смена и сброс
+func ValidatePassword(value string) error {
+	if len(value) < minPasswordLen || len(value) > maxPasswordLen {
+		return fmt.Errorf("must contain from %d to %d bytes", minPasswordLen, maxPasswordLen)
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range value {
+		hasLetter = hasLetter || unicode.IsLetter(r)
+		hasDigit = hasDigit || unicode.IsDigit(r)
+	}
+	if !hasLetter || !hasDigit {
+		return errors.New("must contain at least one letter and one digit")
+	}
+
+	return nil
+}
+
+// при логине политику не применяем: старые пароли могли быть заведены до нее
+func ValidateLoginPassword(value string) error {
+	if value == "" {
+		return errors.New("is required")
+	}
+	if len(value) > maxPasswordLen {
+		return fmt.Errorf("must be at most %d bytes", maxPasswordLen)
+	}
+	return nil
+}
+
+func ValidateID(value int64) error {
+	if value <= 0 {
+		return errors.New("must be a positive number")
+	}
+	return nil
+}
+
+// 0 - приложение по умолчанию, там где app_id необязателен
+func ValidateOptionalID(value int64) error {
+	if value < 0 {
+		return errors.New("must not be negative")
+	}
+	return nil
+}
+
+func ValidateRequired(value string) error {
+	if err := ValidateNotEmpty(value); err != nil {
+		return err
+	}
+	return ValidateOptional(value)
+}
+
+// без ограничения длины: для больших значений вроде JSON от webauthn
+func ValidateNotEmpty(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("is required")
+	}
+	return nil
+}
+
+func ValidateOptional(value string) error {
+	if len(value) > maxValueLength {
+		return fmt.Errorf("must be at most %d characters", maxValueLength)
+	}
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 
 type TaskDistributor interface {
 	DistributeTaskSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opts ...asynq.Option) error
+	DistributeTaskSendPasswordReset(ctx context.Context, payload *PayloadSendPasswordReset, opts ...asynq.Option) error
+	DistributeTaskSendMagicLink(ctx context.Context, payload *PayloadSendMagicLink, opts ...asynq.Option) error
 }
 
 type RedisTaskDistributor struct {
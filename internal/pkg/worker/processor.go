@@ -26,6 +26,8 @@ type TaskProcessor interface {
 	Start() error
 	Shutdown()
 	ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendPasswordReset(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendMagicLink(ctx context.Context, task *asynq.Task) error
 }
 
 type RedisTaskProcessor struct {
@@ -33,10 +35,16 @@ type RedisTaskProcessor struct {
 	userRepo postgres.UserRepository
 	mailRepo postgres.EmailRepositoryI
 	mailer   mail.EmailSender
+
+	passwordResetRepo postgres.PasswordResetRepository
+	// страница фронтенда, куда ведет ссылка из письма для сброса пароля
+	passwordResetURL string
+	// страница фронтенда, которая обменивает ссылку из письма на токены
+	magicLinkURL string
 }
 
 // обработчик задач
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, userRepo postgres.UserRepository, mailRepo postgres.EmailRepositoryI, mailer mail.EmailSender) TaskProcessor {
+func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, userRepo postgres.UserRepository, mailRepo postgres.EmailRepositoryI, mailer mail.EmailSender, passwordResetRepo postgres.PasswordResetRepository, passwordResetURL, magicLinkURL string) TaskProcessor {
 	server := asynq.NewServer(redisOpt, asynq.Config{
 		Queues: map[string]int{
 			QueueCritical: 10,
@@ -56,6 +64,10 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, userRepo postgres.User
 		userRepo: userRepo,
 		mailRepo: mailRepo,
 		mailer:   mailer,
+
+		passwordResetRepo: passwordResetRepo,
+		passwordResetURL:  passwordResetURL,
+		magicLinkURL:      magicLinkURL,
 	}
 
 }
@@ -65,6 +77,8 @@ func (processor *RedisTaskProcessor) Start() error {
 
 	// регистрация задач
 	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessTaskSendVerifyEmail)
+	mux.HandleFunc(TaskSendPasswordReset, processor.ProcessTaskSendPasswordReset)
+	mux.HandleFunc(TaskSendMagicLink, processor.ProcessTaskSendMagicLink)
 
 	return processor.server.Start(mux)
 }
@@ -78,10 +92,11 @@ func RunTaskProcessor(ctx context.Context, waitGroup *errgroup.Group, config *co
 
 	mailRepo := postgres.NewEmailRepository(db)
 	userRepo := postgres.NewUserRepo(db)
+	passwordResetRepo := postgres.NewPasswordResetRepo(db)
 
 	mailer := mail.NewGmailSender(config.SmtpGmail.SenderName, config.SmtpGmail.SenderAddress, config.SmtpGmail.SenderPassword)
 
-	taskProcessor := NewRedisTaskProcessor(redisOpt, userRepo, mailRepo, mailer)
+	taskProcessor := NewRedisTaskProcessor(redisOpt, userRepo, mailRepo, mailer, passwordResetRepo, config.Auth.PasswordResetURL, config.Auth.MagicLinkURL)
 	log.Println("start task processor")
 
 	err := taskProcessor.Start()
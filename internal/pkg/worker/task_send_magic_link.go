@@ -0,0 +1,109 @@
+package worker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"github.com/hibiken/asynq"
+)
+
+// данные задачи
+type PayloadSendMagicLink struct {
+	UserID int64 `json:"user_id"`
+	// ссылка сработает только в браузере, из которого ее запросили
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+const (
+	TaskSendMagicLink = "task:send_magic_link"
+
+	// сколько живет ссылка для входа
+	MagicLinkTTL = 15 * time.Minute
+
+	defaultMagicLinkURL = "http://localhost:8082/magic_link"
+)
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendMagicLink(ctx context.Context, payload *PayloadSendMagicLink, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendMagicLink, jsonPayload, opts...)
+
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Printf("Получена новая задача - письмо со ссылкой для входа. %v, queue: %v, max_retry: %v", task.Type(), info.Queue, info.MaxRetry)
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendMagicLink(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendMagicLink
+
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.userRepo.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return fmt.Errorf("user doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	code, err := generateEmailToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate magic link code: %w", err)
+	}
+
+	hash := sha256.Sum256([]byte(code))
+	link := &domain.MagicLink{
+		UserID:    user.Id,
+		Email:     user.Email,
+		CodeHash:  hash[:],
+		UserAgent: payload.UserAgent,
+		ExpiredAt: time.Now().Add(MagicLinkTTL),
+	}
+	err = processor.mailRepo.CreateMagicLink(ctx, link)
+	if err != nil {
+		return fmt.Errorf("failed to create magic link: %w", err)
+	}
+
+	linkURL := processor.magicLinkURL
+	if linkURL == "" {
+		linkURL = defaultMagicLinkURL
+	}
+	linkURL += "?" + url.Values{
+		"link_id": {strconv.FormatInt(link.ID, 10)},
+		"code":    {code},
+	}.Encode()
+
+	subject := "Вход в аккаунт"
+	content := fmt.Sprintf(`Hello %s,<br/>
+		Click <a href="%s">HERE</a> to log in. The link is valid for %d minutes and works only once.<br/>
+		If you didn't request it, just ignore this email.<br/>
+	`, user.Name, linkURL, int(MagicLinkTTL.Minutes()))
+
+	to := []string{user.Email}
+
+	err = processor.mailer.Sendmail(subject, content, to, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send magic link email: %w", err)
+	}
+
+	log.Printf("Задача успешно выполнена, письмо со ссылкой для входа отправлено. type: %v, user_id: %d", task.Type(), user.Id)
+	return nil
+}
@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"github.com/hibiken/asynq"
+)
+
+// данные задачи
+type PayloadSendPasswordReset struct {
+	UserID int64 `json:"user_id"`
+}
+
+const (
+	TaskSendPasswordReset = "task:send_password_reset"
+
+	// сколько живет ссылка из письма
+	PasswordResetTTL = 30 * time.Minute
+
+	defaultPasswordResetURL = "http://localhost:8082/reset_password"
+)
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendPasswordReset(ctx context.Context, payload *PayloadSendPasswordReset, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendPasswordReset, jsonPayload, opts...)
+
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Printf("Получена новая задача - письмо для сброса пароля. %v, payload: %s, queue: %v, max_retry: %v", task.Type(), task.Payload(), info.Queue, info.MaxRetry)
+	return nil
+}
+
+// токен генерим здесь, а не при постановке задачи: открытый токен не должен лежать в очереди redis.
+// при повторе задачи новый токен гасит предыдущий, так что живой остается только ссылка из доставленного письма
+func (processor *RedisTaskProcessor) ProcessTaskSendPasswordReset(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendPasswordReset
+
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.userRepo.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return fmt.Errorf("user doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	token, err := generateEmailToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	hash := sha256.Sum256([]byte(token))
+	err = processor.passwordResetRepo.CreatePasswordResetTx(ctx, &domain.PasswordReset{
+		UserID:    user.Id,
+		Hash:      hash[:],
+		ExpiredAt: time.Now().Add(PasswordResetTTL),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+
+	resetURL := processor.passwordResetURL
+	if resetURL == "" {
+		resetURL = defaultPasswordResetURL
+	}
+	resetURL += "?token=" + url.QueryEscape(token)
+
+	subject := "Сброс пароля"
+	content := fmt.Sprintf(`Hello %s,<br/>
+		Someone requested a password reset for your account.<br/>
+		Click <a href="%s">HERE</a> to set a new password. The link is valid for %d minutes.<br/>
+		If it wasn't you, just ignore this email.<br/>
+	`, user.Name, resetURL, int(PasswordResetTTL.Minutes()))
+
+	to := []string{user.Email}
+
+	err = processor.mailer.Sendmail(subject, content, to, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	log.Printf("Задача успешно выполнена, письмо для сброса пароля отправлено. type: %v, user_id: %d", task.Type(), user.Id)
+	return nil
+}
+
+// случайный токен для ссылок из писем (сброс пароля, вход по ссылке)
+func generateEmailToken() (string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(randomBytes), nil
+}
@@ -8,14 +8,16 @@ import (
 	"log"
 
 	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/pkg/pb"
 	"github.com/Iowel/app-auth-service/pkg/util"
 
 	"github.com/hibiken/asynq"
 )
 
-// данные задачи 
+// данные задачи
 type PayloadSendVerifyEmail struct {
-	Name string `json:"name"`
+	Name   string `json:"name"`
+	UserID int64  `json:"user_id,omitempty"`
 }
 
 // описание задачи
@@ -53,8 +55,14 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
 	}
 
-	// извлекаем запись юзверя из базы
-	user, err := processor.userRepo.GetUserByName(ctx, payload.Name)
+	// извлекаем запись юзверя из базы (по id, если он есть - имена не уникальны)
+	var user *pb.User
+	var err error
+	if payload.UserID != 0 {
+		user, err = processor.userRepo.GetUserByID(ctx, payload.UserID)
+	} else {
+		user, err = processor.userRepo.GetUserByName(ctx, payload.Name)
+	}
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return fmt.Errorf("user doesn't exist: %w", asynq.SkipRetry)
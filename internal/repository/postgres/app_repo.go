@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ AppRepository = &appRepo{}
+
+type AppRepository interface {
+	GetApp(ctx context.Context, id int64) (*domain.App, error)
+}
+
+type appRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAppRepo(db *pgxpool.Pool) AppRepository {
+	return &appRepo{
+		db: db,
+	}
+}
+
+func (r *appRepo) GetApp(ctx context.Context, id int64) (*domain.App, error) {
+	const op = "repository.postgres.GetApp"
+
+	query := `
+		SELECT id, name, is_active, created_at
+		FROM apps
+		WHERE id = $1
+	`
+
+	var app domain.App
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&app.ID,
+		&app.Name,
+		&app.IsActive,
+		&app.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrInvalidAppID)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &app, nil
+}
@@ -16,11 +16,13 @@ import (
 
 var _ EmailRepositoryI = &emailRepository{}
 
-
 type EmailRepositoryI interface {
 	CreateVerifyEmail(ctx context.Context, arg domain.CreateVerifyEmailParams) (*domain.VerifyEmail, error)
 	UpdateVerifyEmail(ctx context.Context, arg domain.UpdateVerifyEmailParams) (*domain.VerifyEmail, error)
 	VerifyEmailTx(ctx context.Context, arg domain.VerifyEmailTxParams) (VerifyEmailTxResult, error)
+
+	CreateMagicLink(ctx context.Context, link *domain.MagicLink) error
+	ConsumeMagicLinkTx(ctx context.Context, arg domain.ConsumeMagicLinkTxParams) (*pb.User, error)
 }
 
 type emailRepository struct {
@@ -122,6 +124,8 @@ func (r *emailRepository) VerifyEmailTx(ctx context.Context, arg domain.VerifyEm
 		  AND secret_code = $2
 		  AND is_used = FALSE
 		  AND expired_at > now()
+		  -- письмо могло уйти на адрес, который юзверь с тех пор сменил
+		  AND email = (SELECT email FROM users WHERE users.id = verify_emails.user_id)
 		RETURNING id, user_id, email, secret_code, is_used, created_at, expired_at;
 	`
 
@@ -149,7 +153,7 @@ func (r *emailRepository) VerifyEmailTx(ctx context.Context, arg domain.VerifyEm
 		UPDATE users
 		SET is_email_verified = TRUE
 		WHERE id = $1
-		RETURNING id, email, name, password, is_email_verified, created_at, updated_at;
+		RETURNING id, email, name, is_email_verified, app_id, created_at, updated_at;
 	`
 
 	var user pb.User
@@ -161,8 +165,8 @@ func (r *emailRepository) VerifyEmailTx(ctx context.Context, arg domain.VerifyEm
 		&user.Id,
 		&user.Email,
 		&user.Name,
-		&user.Password,
 		&user.Isemailverified,
+		&user.AppId,
 		&createdAt,
 		&updatedAt,
 	)
@@ -172,7 +176,7 @@ func (r *emailRepository) VerifyEmailTx(ctx context.Context, arg domain.VerifyEm
 
 	user.CreatedAt = timestamppb.New(createdAt)
 	user.UpdatedAt = timestamppb.New(updatedAt)
-	
+
 	result.User = &user
 
 	if err := tx.Commit(ctx); err != nil {
@@ -181,3 +185,89 @@ func (r *emailRepository) VerifyEmailTx(ctx context.Context, arg domain.VerifyEm
 
 	return result, nil
 }
+
+func (e *emailRepository) CreateMagicLink(ctx context.Context, link *domain.MagicLink) error {
+	const op = "repository.postgres.CreateMagicLink"
+
+	query := `
+		INSERT INTO magic_links (user_id, email, code_hash, user_agent, expired_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;
+	`
+
+	err := e.Db.QueryRow(ctx, query, link.UserID, link.Email, link.CodeHash, link.UserAgent, link.ExpiredAt).Scan(&link.ID, &link.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// та же одноразовость, что и у VerifyEmailTx; переход по ссылке заодно подтверждает email
+func (e *emailRepository) ConsumeMagicLinkTx(ctx context.Context, arg domain.ConsumeMagicLinkTxParams) (*pb.User, error) {
+	const op = "repository.postgres.ConsumeMagicLinkTx"
+
+	tx, err := e.Db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// ссылку из чужого браузера не гасим - владелец еще сможет ей воспользоваться
+	consumeQuery := `
+		UPDATE magic_links
+		SET is_used = TRUE
+		WHERE id = $1
+		  AND code_hash = $2
+		  AND (user_agent = '' OR user_agent = $3)
+		  AND is_used = FALSE
+		  AND expired_at > now()
+		  -- после смены email ссылка на старый адрес не должна ни логинить, ни подтверждать новый
+		  AND email = (SELECT email FROM users WHERE users.id = magic_links.user_id)
+		RETURNING user_id;
+	`
+
+	var userID int64
+	err = tx.QueryRow(ctx, consumeQuery, arg.LinkID, arg.CodeHash, arg.UserAgent).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrInvalidMagicLink)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	updateUserQuery := `
+		UPDATE users
+		SET is_email_verified = TRUE
+		WHERE id = $1
+		RETURNING id, email, name, role, avatar, is_email_verified, app_id, created_at, updated_at;
+	`
+
+	var user pb.User
+	var createdAt time.Time
+	var updatedAt time.Time
+
+	err = tx.QueryRow(ctx, updateUserQuery, userID).Scan(
+		&user.Id,
+		&user.Email,
+		&user.Name,
+		&user.Role,
+		&user.Avatar,
+		&user.Isemailverified,
+		&user.AppId,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to update user: %w", op, err)
+	}
+
+	user.CreatedAt = timestamppb.New(createdAt)
+	user.UpdatedAt = timestamppb.New(updatedAt)
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	return &user, nil
+}
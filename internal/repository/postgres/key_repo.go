@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ KeyRepository = &keyRepo{}
+
+type KeyRepository interface {
+	ListSigningKeys(ctx context.Context, retention time.Duration) ([]*domain.SigningKey, error)
+	CreateSigningKeyIfNone(ctx context.Context, key *domain.SigningKey) (bool, error)
+	ScheduleSigningKeyTx(ctx context.Context, key *domain.SigningKey, activatesAt time.Time) error
+	ActivateDueSigningKeyTx(ctx context.Context) (string, error)
+}
+
+type keyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewKeyRepo(db *pgxpool.Pool) KeyRepository {
+	return &keyRepo{
+		db: db,
+	}
+}
+
+// активный ключ, ожидающий активации и выведенные из оборота, которыми еще могут быть подписаны живые токены
+func (r *keyRepo) ListSigningKeys(ctx context.Context, retention time.Duration) ([]*domain.SigningKey, error) {
+	const op = "repository.postgres.ListSigningKeys"
+
+	query := `
+		SELECT kid, algorithm, private_key, public_key, is_active, created_at, retired_at, activates_at
+		FROM signing_keys
+		WHERE is_active OR retired_at IS NULL OR retired_at > $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, time.Now().Add(-retention))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var keys []*domain.SigningKey
+	for rows.Next() {
+		var k domain.SigningKey
+		err := rows.Scan(
+			&k.Kid,
+			&k.Algorithm,
+			&k.PrivateKey,
+			&k.PublicKey,
+			&k.IsActive,
+			&k.CreatedAt,
+			&k.RetiredAt,
+			&k.ActivatesAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		keys = append(keys, &k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+// первый ключ при старте; если другая реплика успела раньше - ничего не делаем
+func (r *keyRepo) CreateSigningKeyIfNone(ctx context.Context, key *domain.SigningKey) (bool, error) {
+	const op = "repository.postgres.CreateSigningKeyIfNone"
+
+	query := `
+		INSERT INTO signing_keys (kid, algorithm, private_key, public_key, is_active)
+		VALUES ($1, $2, $3, $4, TRUE)
+		ON CONFLICT (is_active) WHERE is_active DO NOTHING
+	`
+
+	tag, err := r.db.Exec(ctx, query, key.Kid, key.Algorithm, key.PrivateKey, key.PublicKey)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// новый ключ публикуем заранее: к моменту активации его публичную часть загрузят все реплики
+func (r *keyRepo) ScheduleSigningKeyTx(ctx context.Context, key *domain.SigningKey, activatesAt time.Time) error {
+	const op = "repository.postgres.ScheduleSigningKeyTx"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// повторная ротация заменяет еще не активированный ключ: им ничего не подписано
+	deleteQuery := `
+		DELETE FROM signing_keys
+		WHERE NOT is_active AND retired_at IS NULL
+	`
+	if _, err := tx.Exec(ctx, deleteQuery); err != nil {
+		return fmt.Errorf("%s: failed to delete pending key: %w", op, err)
+	}
+
+	insertQuery := `
+		INSERT INTO signing_keys (kid, algorithm, private_key, public_key, is_active, activates_at)
+		VALUES ($1, $2, $3, $4, FALSE, $5)
+		RETURNING created_at
+	`
+	err = tx.QueryRow(ctx, insertQuery, key.Kid, key.Algorithm, key.PrivateKey, key.PublicKey, activatesAt).Scan(&key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: failed to insert key: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	key.ActivatesAt = &activatesAt
+	return nil
+}
+
+// подошло время - меняем активный ключ; реплики делают это наперегонки, побеждает одна. Возвращает kid или ""
+func (r *keyRepo) ActivateDueSigningKeyTx(ctx context.Context) (string, error) {
+	const op = "repository.postgres.ActivateDueSigningKeyTx"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return "", fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// FOR UPDATE: вторая реплика дождется первой и увидит, что ключ уже активен
+	selectQuery := `
+		SELECT kid
+		FROM signing_keys
+		WHERE NOT is_active AND retired_at IS NULL AND activates_at <= now()
+		ORDER BY activates_at DESC
+		LIMIT 1
+		FOR UPDATE
+	`
+	var kid string
+	err = tx.QueryRow(ctx, selectQuery).Scan(&kid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// выводим текущий ключ из оборота, проверять им подписи еще можно
+	retireQuery := `
+		UPDATE signing_keys
+		SET is_active = FALSE, retired_at = now()
+		WHERE is_active
+	`
+	if _, err := tx.Exec(ctx, retireQuery); err != nil {
+		return "", fmt.Errorf("%s: failed to retire key: %w", op, err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE signing_keys SET is_active = TRUE WHERE kid = $1`, kid); err != nil {
+		return "", fmt.Errorf("%s: failed to activate key: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	return kid, nil
+}
@@ -0,0 +1,214 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ OAuthRepository = &oauthRepo{}
+
+type OAuthRepository interface {
+	GetClient(ctx context.Context, clientID string) (*domain.OAuthClient, error)
+	CreateServiceClient(ctx context.Context, client *domain.OAuthClient) error
+	UpdateServiceClientSecret(ctx context.Context, clientID string, secretHash []byte) (*domain.OAuthClient, error)
+	CreateAuthorizationCode(ctx context.Context, code *domain.AuthorizationCode) error
+	ConsumeAuthorizationCode(ctx context.Context, codeHash []byte) (*domain.AuthorizationCode, error)
+	GetConsent(ctx context.Context, userID int64, clientID string) ([]string, error)
+	SaveConsent(ctx context.Context, userID int64, clientID string, scopes []string) error
+}
+
+type oauthRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewOAuthRepo(db *pgxpool.Pool) OAuthRepository {
+	return &oauthRepo{
+		db: db,
+	}
+}
+
+func (r *oauthRepo) GetClient(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+	const op = "repository.postgres.GetClient"
+
+	query := `
+		SELECT client_id, client_secret_hash, name, redirect_uris, allowed_scopes, is_first_party, is_service, secret_rotated_at, created_at
+		FROM oauth_clients
+		WHERE client_id = $1
+	`
+
+	var c domain.OAuthClient
+	err := r.db.QueryRow(ctx, query, clientID).Scan(
+		&c.ClientID,
+		&c.SecretHash,
+		&c.Name,
+		&c.RedirectURIs,
+		&c.AllowedScopes,
+		&c.IsFirstParty,
+		&c.IsService,
+		&c.SecretRotatedAt,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidClient
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &c, nil
+}
+
+func (r *oauthRepo) CreateServiceClient(ctx context.Context, client *domain.OAuthClient) error {
+	const op = "repository.postgres.CreateServiceClient"
+
+	query := `
+		INSERT INTO oauth_clients (client_id, client_secret_hash, name, allowed_scopes, is_service)
+		VALUES ($1, $2, $3, $4, TRUE)
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRow(ctx, query, client.ClientID, client.SecretHash, client.Name, client.AllowedScopes).Scan(&client.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	client.IsService = true
+
+	return nil
+}
+
+// секрет меняем только машинным клиентам; у остальных он заводится вместе с приложением
+func (r *oauthRepo) UpdateServiceClientSecret(ctx context.Context, clientID string, secretHash []byte) (*domain.OAuthClient, error) {
+	const op = "repository.postgres.UpdateServiceClientSecret"
+
+	query := `
+		UPDATE oauth_clients
+		SET client_secret_hash = $2, secret_rotated_at = now()
+		WHERE client_id = $1 AND is_service = TRUE
+		RETURNING client_id, client_secret_hash, name, redirect_uris, allowed_scopes, is_first_party, is_service, secret_rotated_at, created_at
+	`
+
+	var c domain.OAuthClient
+	err := r.db.QueryRow(ctx, query, clientID, secretHash).Scan(
+		&c.ClientID,
+		&c.SecretHash,
+		&c.Name,
+		&c.RedirectURIs,
+		&c.AllowedScopes,
+		&c.IsFirstParty,
+		&c.IsService,
+		&c.SecretRotatedAt,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidClient
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &c, nil
+}
+
+func (r *oauthRepo) CreateAuthorizationCode(ctx context.Context, code *domain.AuthorizationCode) error {
+	const op = "repository.postgres.CreateAuthorizationCode"
+
+	query := `
+		INSERT INTO oauth_authorization_codes (code_hash, client_id, user_id, redirect_uri, scope, code_challenge, code_challenge_method, nonce, expired_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		code.Hash,
+		code.ClientID,
+		code.UserID,
+		code.RedirectURI,
+		code.Scope,
+		code.CodeChallenge,
+		code.CodeChallengeMethod,
+		code.Nonce,
+		code.ExpiredAt,
+	).Scan(&code.ID, &code.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// код одноразовый: помечаем использованным и возвращаем одним запросом, чтобы два обмена не прошли параллельно
+func (r *oauthRepo) ConsumeAuthorizationCode(ctx context.Context, codeHash []byte) (*domain.AuthorizationCode, error) {
+	const op = "repository.postgres.ConsumeAuthorizationCode"
+
+	query := `
+		UPDATE oauth_authorization_codes
+		SET is_used = TRUE
+		WHERE code_hash = $1 AND is_used = FALSE AND expired_at > now()
+		RETURNING id, client_id, user_id, redirect_uri, scope, code_challenge, code_challenge_method, nonce, created_at, expired_at
+	`
+
+	var c domain.AuthorizationCode
+	err := r.db.QueryRow(ctx, query, codeHash).Scan(
+		&c.ID,
+		&c.ClientID,
+		&c.UserID,
+		&c.RedirectURI,
+		&c.Scope,
+		&c.CodeChallenge,
+		&c.CodeChallengeMethod,
+		&c.Nonce,
+		&c.CreatedAt,
+		&c.ExpiredAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidGrant
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.Hash = codeHash
+	return &c, nil
+}
+
+// scopes, на которые юзверь уже согласился для этого клиента
+func (r *oauthRepo) GetConsent(ctx context.Context, userID int64, clientID string) ([]string, error) {
+	const op = "repository.postgres.GetConsent"
+
+	query := `SELECT scopes FROM oauth_consents WHERE user_id = $1 AND client_id = $2`
+
+	var scopes []string
+	err := r.db.QueryRow(ctx, query, userID, clientID).Scan(&scopes)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return scopes, nil
+}
+
+func (r *oauthRepo) SaveConsent(ctx context.Context, userID int64, clientID string, scopes []string) error {
+	const op = "repository.postgres.SaveConsent"
+
+	query := `
+		INSERT INTO oauth_consents (user_id, client_id, scopes)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, client_id)
+		DO UPDATE SET scopes = EXCLUDED.scopes, updated_at = now()
+	`
+
+	_, err := r.db.Exec(ctx, query, userID, clientID, scopes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ PasskeyRepository = &passkeyRepo{}
+
+type PasskeyRepository interface {
+	CreatePasskey(ctx context.Context, passkey *domain.Passkey) error
+	ListPasskeys(ctx context.Context, userID int64) ([]*domain.Passkey, error)
+	UpdatePasskeyUsage(ctx context.Context, id int64, signCount uint32, flags uint8) error
+
+	CreateCeremony(ctx context.Context, ceremony *domain.PasskeyCeremony) error
+	ConsumeCeremony(ctx context.Context, tokenHash []byte, kind string) (*domain.PasskeyCeremony, error)
+}
+
+type passkeyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPasskeyRepo(db *pgxpool.Pool) PasskeyRepository {
+	return &passkeyRepo{
+		db: db,
+	}
+}
+
+func (r *passkeyRepo) CreatePasskey(ctx context.Context, passkey *domain.Passkey) error {
+	const op = "repository.postgres.CreatePasskey"
+
+	query := `
+		INSERT INTO webauthn_credentials (user_id, credential_id, public_key, attestation_type, transports, flags, aaguid, sign_count, name)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		passkey.UserID,
+		passkey.CredentialID,
+		passkey.PublicKey,
+		passkey.AttestationType,
+		passkey.Transports,
+		int16(passkey.Flags),
+		passkey.AAGUID,
+		int64(passkey.SignCount),
+		passkey.Name,
+	).Scan(&passkey.ID, &passkey.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrPasskeyExists
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *passkeyRepo) ListPasskeys(ctx context.Context, userID int64) ([]*domain.Passkey, error) {
+	const op = "repository.postgres.ListPasskeys"
+
+	query := `
+		SELECT id, user_id, credential_id, public_key, attestation_type, transports, flags, aaguid, sign_count, name, created_at, last_used_at
+		FROM webauthn_credentials
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var passkeys []*domain.Passkey
+	for rows.Next() {
+		var (
+			p         domain.Passkey
+			flags     int16
+			signCount int64
+		)
+		err := rows.Scan(
+			&p.ID,
+			&p.UserID,
+			&p.CredentialID,
+			&p.PublicKey,
+			&p.AttestationType,
+			&p.Transports,
+			&flags,
+			&p.AAGUID,
+			&signCount,
+			&p.Name,
+			&p.CreatedAt,
+			&p.LastUsedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		p.Flags = uint8(flags)
+		p.SignCount = uint32(signCount)
+
+		passkeys = append(passkeys, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return passkeys, nil
+}
+
+// после успешного входа запоминаем счетчик подписей, по нему ловим клонированные ключи
+func (r *passkeyRepo) UpdatePasskeyUsage(ctx context.Context, id int64, signCount uint32, flags uint8) error {
+	const op = "repository.postgres.UpdatePasskeyUsage"
+
+	query := `
+		UPDATE webauthn_credentials
+		SET sign_count = $2, flags = $3, last_used_at = now()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, int64(signCount), int16(flags))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *passkeyRepo) CreateCeremony(ctx context.Context, ceremony *domain.PasskeyCeremony) error {
+	const op = "repository.postgres.CreateCeremony"
+
+	query := `
+		INSERT INTO webauthn_ceremonies (token_hash, user_id, kind, session_data, expired_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		ceremony.Hash,
+		ceremony.UserID,
+		ceremony.Kind,
+		ceremony.SessionData,
+		ceremony.ExpiredAt,
+	).Scan(&ceremony.ID, &ceremony.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// церемония одноразовая: забираем и помечаем использованной одним запросом
+func (r *passkeyRepo) ConsumeCeremony(ctx context.Context, tokenHash []byte, kind string) (*domain.PasskeyCeremony, error) {
+	const op = "repository.postgres.ConsumeCeremony"
+
+	query := `
+		UPDATE webauthn_ceremonies
+		SET is_used = TRUE
+		WHERE token_hash = $1 AND kind = $2 AND is_used = FALSE AND expired_at > now()
+		RETURNING id, token_hash, user_id, kind, session_data, is_used, created_at, expired_at
+	`
+
+	var c domain.PasskeyCeremony
+	err := r.db.QueryRow(ctx, query, tokenHash, kind).Scan(
+		&c.ID,
+		&c.Hash,
+		&c.UserID,
+		&c.Kind,
+		&c.SessionData,
+		&c.IsUsed,
+		&c.CreatedAt,
+		&c.ExpiredAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidPasskeyCeremony
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &c, nil
+}
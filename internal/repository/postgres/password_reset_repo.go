@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ PasswordResetRepository = &passwordResetRepo{}
+
+type PasswordResetRepository interface {
+	CreatePasswordResetTx(ctx context.Context, reset *domain.PasswordReset) error
+	ResetPasswordTx(ctx context.Context, tokenHash []byte, passwordHash string) (int64, error)
+}
+
+type passwordResetRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPasswordResetRepo(db *pgxpool.Pool) PasswordResetRepository {
+	return &passwordResetRepo{
+		db: db,
+	}
+}
+
+// действует только последняя ссылка: задача отправки перезапускается, и ссылки из неотправленных писем не должны оставаться живыми
+func (r *passwordResetRepo) CreatePasswordResetTx(ctx context.Context, reset *domain.PasswordReset) error {
+	const op = "repository.postgres.CreatePasswordResetTx"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE password_resets SET is_used = TRUE WHERE user_id = $1 AND is_used = FALSE`, reset.UserID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `
+		INSERT INTO password_resets (user_id, token_hash, expired_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRow(ctx, query, reset.UserID, reset.Hash, reset.ExpiredAt).Scan(&reset.ID, &reset.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	return nil
+}
+
+// гасим токен, меняем пароль и выкидываем юзверя со всех устройств одной транзакцией; возвращает id юзверя
+func (r *passwordResetRepo) ResetPasswordTx(ctx context.Context, tokenHash []byte, passwordHash string) (int64, error) {
+	const op = "repository.postgres.ResetPasswordTx"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	consumeQuery := `
+		UPDATE password_resets
+		SET is_used = TRUE
+		WHERE token_hash = $1 AND is_used = FALSE AND expired_at > now()
+		RETURNING user_id
+	`
+	var userID int64
+	err = tx.QueryRow(ctx, consumeQuery, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrInvalidPasswordResetToken
+		}
+		return 0, fmt.Errorf("%s: failed to consume reset token: %w", op, err)
+	}
+
+	// остальные письма со ссылками на сброс тоже больше не работают
+	if _, err := tx.Exec(ctx, `UPDATE password_resets SET is_used = TRUE WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("%s: failed to invalidate reset tokens: %w", op, err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET password = $2, updated_at = now() WHERE id = $1`, userID, passwordHash); err != nil {
+		return 0, fmt.Errorf("%s: failed to update password: %w", op, err)
+	}
+
+	// токены сессий удалятся каскадом, но бывают и токены без сессии
+	if _, err := tx.Exec(ctx, `DELETE FROM sessions WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("%s: failed to delete sessions: %w", op, err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM tokens WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("%s: failed to delete tokens: %w", op, err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET is_revoked = TRUE WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("%s: failed to revoke refresh tokens: %w", op, err)
+	}
+	// personal access token мог выпустить тот, кто угнал аккаунт
+	if _, err := tx.Exec(ctx, `DELETE FROM personal_access_tokens WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("%s: failed to delete personal access tokens: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/pkg/pb"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ PersonalAccessTokenRepository = &personalAccessTokenRepo{}
+
+type PersonalAccessTokenRepository interface {
+	CreatePersonalAccessToken(ctx context.Context, pat *domain.PersonalAccessToken, tokenHash []byte) error
+	ListPersonalAccessTokens(ctx context.Context, userID int64) ([]*domain.PersonalAccessToken, error)
+	GetPersonalAccessToken(ctx context.Context, token string) (*domain.PersonalAccessToken, *pb.User, error)
+	TouchPersonalAccessToken(ctx context.Context, id int64) error
+	DeletePersonalAccessToken(ctx context.Context, userID, id int64) error
+}
+
+type personalAccessTokenRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPersonalAccessTokenRepo(db *pgxpool.Pool) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepo{
+		db: db,
+	}
+}
+
+func (r *personalAccessTokenRepo) CreatePersonalAccessToken(ctx context.Context, pat *domain.PersonalAccessToken, tokenHash []byte) error {
+	const op = "repository.postgres.CreatePersonalAccessToken"
+
+	query := `
+		INSERT INTO personal_access_tokens (user_id, name, token_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query, pat.UserID, pat.Name, tokenHash, pat.Scopes, pat.ExpiresAt).Scan(
+		&pat.ID,
+		&pat.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// истекшие тоже показываем, чтобы юзверь видел, что пора выпустить новый
+func (r *personalAccessTokenRepo) ListPersonalAccessTokens(ctx context.Context, userID int64) ([]*domain.PersonalAccessToken, error) {
+	const op = "repository.postgres.ListPersonalAccessTokens"
+
+	query := `
+		SELECT id, user_id, name, scopes, expires_at, last_used_at, created_at
+		FROM personal_access_tokens
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var pats []*domain.PersonalAccessToken
+	for rows.Next() {
+		var pat domain.PersonalAccessToken
+		err := rows.Scan(
+			&pat.ID,
+			&pat.UserID,
+			&pat.Name,
+			&pat.Scopes,
+			&pat.ExpiresAt,
+			&pat.LastUsedAt,
+			&pat.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		pats = append(pats, &pat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pats, nil
+}
+
+// действующий токен вместе с его владельцем; истекший считаем несуществующим
+func (r *personalAccessTokenRepo) GetPersonalAccessToken(ctx context.Context, token string) (*domain.PersonalAccessToken, *pb.User, error) {
+	const op = "repository.postgres.GetPersonalAccessToken"
+
+	tokenHash := sha256.Sum256([]byte(token))
+
+	query := `
+		SELECT
+			p.id, p.user_id, p.name, p.scopes, p.expires_at, p.last_used_at, p.created_at,
+			u.id, u.name, u.email, u.role, u.is_email_verified, u.app_id
+		FROM
+			personal_access_tokens p
+		INNER JOIN users u ON u.id = p.user_id
+		WHERE
+			p.token_hash = $1 AND (p.expires_at IS NULL OR p.expires_at > now())
+	`
+
+	var pat domain.PersonalAccessToken
+	var user pb.User
+
+	err := r.db.QueryRow(ctx, query, tokenHash[:]).Scan(
+		&pat.ID,
+		&pat.UserID,
+		&pat.Name,
+		&pat.Scopes,
+		&pat.ExpiresAt,
+		&pat.LastUsedAt,
+		&pat.CreatedAt,
+		&user.Id,
+		&user.Name,
+		&user.Email,
+		&user.Role,
+		&user.Isemailverified,
+		&user.AppId,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, fmt.Errorf("%s: %w", op, domain.ErrTokenNotFound)
+		}
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &pat, &user, nil
+}
+
+func (r *personalAccessTokenRepo) TouchPersonalAccessToken(ctx context.Context, id int64) error {
+	const op = "repository.postgres.TouchPersonalAccessToken"
+
+	query := `UPDATE personal_access_tokens SET last_used_at = now() WHERE id = $1`
+
+	if _, err := r.db.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// удаляем только свой токен, чужой id выглядит как несуществующий
+func (r *personalAccessTokenRepo) DeletePersonalAccessToken(ctx context.Context, userID, id int64) error {
+	const op = "repository.postgres.DeletePersonalAccessToken"
+
+	query := `DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2`
+
+	tag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrPersonalAccessTokenNotFound)
+	}
+
+	return nil
+}
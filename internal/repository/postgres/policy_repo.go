@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ PolicyRepository = &policyRepo{}
+
+type PolicyRepository interface {
+	ListEnabledPolicies(ctx context.Context) ([]*domain.Policy, error)
+}
+
+type policyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPolicyRepo(db *pgxpool.Pool) PolicyRepository {
+	return &policyRepo{
+		db: db,
+	}
+}
+
+func (r *policyRepo) ListEnabledPolicies(ctx context.Context) ([]*domain.Policy, error) {
+	const op = "repository.postgres.ListEnabledPolicies"
+
+	query := `
+		SELECT id, name, description, action, effect, expression, priority, is_enabled, created_at, updated_at
+		FROM policies
+		WHERE is_enabled = TRUE
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var policies []*domain.Policy
+	for rows.Next() {
+		var p domain.Policy
+		err := rows.Scan(
+			&p.ID,
+			&p.Name,
+			&p.Description,
+			&p.Action,
+			&p.Effect,
+			&p.Expression,
+			&p.Priority,
+			&p.IsEnabled,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		policies = append(policies, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return policies, nil
+}
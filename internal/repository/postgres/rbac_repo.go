@@ -0,0 +1,370 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ RBACRepository = &rbacRepo{}
+
+// методы, меняющие права роли, возвращают id юзверей с этой ролью - им надо сбросить кэш прав.
+// users.role (claim role в токенах, X-User-Role) синхронизируется с user_roles в той же транзакции
+type RBACRepository interface {
+	ListRoles(ctx context.Context) ([]*domain.Role, error)
+	CreateRoleTx(ctx context.Context, arg domain.CreateRoleParams) (*domain.Role, error)
+	DeleteRoleTx(ctx context.Context, name string) ([]int64, error)
+	GrantPermissionTx(ctx context.Context, role, permission string) ([]int64, error)
+	RevokePermission(ctx context.Context, role, permission string) ([]int64, error)
+
+	AssignRoleTx(ctx context.Context, userID int64, role string) error
+	RevokeRoleTx(ctx context.Context, userID int64, role string) error
+	GetUserPermissions(ctx context.Context, userID int64) (*domain.UserPermissions, error)
+}
+
+type rbacRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRBACRepo(db *pgxpool.Pool) RBACRepository {
+	return &rbacRepo{
+		db: db,
+	}
+}
+
+func (r *rbacRepo) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	const op = "repository.postgres.ListRoles"
+
+	query := `
+		SELECT r.id, r.name, r.description, r.created_at,
+			COALESCE(array_agg(p.name ORDER BY p.name) FILTER (WHERE p.name IS NOT NULL), '{}')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id
+		GROUP BY r.id
+		ORDER BY r.name
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var roles []*domain.Role
+	for rows.Next() {
+		var role domain.Role
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.Permissions); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		roles = append(roles, &role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, nil
+}
+
+func (r *rbacRepo) CreateRoleTx(ctx context.Context, arg domain.CreateRoleParams) (*domain.Role, error) {
+	const op = "repository.postgres.CreateRoleTx"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	role := domain.Role{
+		Name:        arg.Name,
+		Description: arg.Description,
+		Permissions: []string{},
+	}
+
+	query := `
+		INSERT INTO roles (name, description)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(ctx, query, arg.Name, arg.Description).Scan(&role.ID, &role.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, domain.ErrRoleExists
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, permission := range arg.Permissions {
+		if err := grantPermission(ctx, tx, role.ID, permission); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		role.Permissions = append(role.Permissions, permission)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	return &role, nil
+}
+
+func (r *rbacRepo) DeleteRoleTx(ctx context.Context, name string) ([]int64, error) {
+	const op = "repository.postgres.DeleteRoleTx"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// user_roles удалятся каскадом, поэтому юзверей собираем в том же запросе
+	query := `
+		WITH deleted AS (
+			DELETE FROM roles WHERE name = $1 RETURNING id
+		)
+		SELECT deleted.id, COALESCE(array_agg(ur.user_id) FILTER (WHERE ur.user_id IS NOT NULL), '{}')
+		FROM deleted
+		LEFT JOIN user_roles ur ON ur.role_id = deleted.id
+		GROUP BY deleted.id
+	`
+
+	var roleID int64
+	var userIDs []int64
+	err = tx.QueryRow(ctx, query, name).Scan(&roleID, &userIDs)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := syncUserRoles(ctx, tx, userIDs...); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	return userIDs, nil
+}
+
+// право создаем, если его еще нет: список прав задают сами сервисы, которые их проверяют
+func (r *rbacRepo) GrantPermissionTx(ctx context.Context, role, permission string) ([]int64, error) {
+	const op = "repository.postgres.GrantPermissionTx"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	roleID, err := roleIDByName(ctx, tx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := grantPermission(ctx, tx, roleID, permission); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	userIDs, err := roleUserIDs(ctx, tx, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	return userIDs, nil
+}
+
+func (r *rbacRepo) RevokePermission(ctx context.Context, role, permission string) ([]int64, error) {
+	const op = "repository.postgres.RevokePermission"
+
+	roleID, err := roleIDByName(ctx, r.db, role)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		DELETE FROM role_permissions
+		WHERE role_id = $1 AND permission_id = (SELECT id FROM permissions WHERE name = $2)
+	`
+	if _, err := r.db.Exec(ctx, query, roleID, permission); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	userIDs, err := roleUserIDs(ctx, r.db, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userIDs, nil
+}
+
+func (r *rbacRepo) AssignRoleTx(ctx context.Context, userID int64, role string) error {
+	const op = "repository.postgres.AssignRoleTx"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	roleID, err := roleIDByName(ctx, tx, role)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO user_roles (user_id, role_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, query, userID, roleID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := syncUserRoles(ctx, tx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *rbacRepo) RevokeRoleTx(ctx context.Context, userID int64, role string) error {
+	const op = "repository.postgres.RevokeRoleTx"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	roleID, err := roleIDByName(ctx, tx, role)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_roles WHERE user_id = $1 AND role_id = $2`, userID, roleID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := syncUserRoles(ctx, tx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *rbacRepo) GetUserPermissions(ctx context.Context, userID int64) (*domain.UserPermissions, error) {
+	const op = "repository.postgres.GetUserPermissions"
+
+	query := `
+		SELECT
+			COALESCE(array_agg(DISTINCT r.name) FILTER (WHERE r.name IS NOT NULL), '{}'),
+			COALESCE(array_agg(DISTINCT p.name) FILTER (WHERE p.name IS NOT NULL), '{}')
+		FROM user_roles ur
+		JOIN roles r ON r.id = ur.role_id
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id
+		WHERE ur.user_id = $1
+	`
+
+	perms := domain.UserPermissions{UserID: userID}
+	if err := r.db.QueryRow(ctx, query, userID).Scan(&perms.Roles, &perms.Permissions); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &perms, nil
+}
+
+// общее для пула и транзакции
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func roleIDByName(ctx context.Context, q querier, name string) (int64, error) {
+	var id int64
+	err := q.QueryRow(ctx, `SELECT id FROM roles WHERE name = $1`, name).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrRoleNotFound
+		}
+		return 0, fmt.Errorf("failed to get role: %w", err)
+	}
+	return id, nil
+}
+
+func grantPermission(ctx context.Context, q querier, roleID int64, permission string) error {
+	query := `
+		WITH permission AS (
+			INSERT INTO permissions (name) VALUES ($2)
+			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		)
+		INSERT INTO role_permissions (role_id, permission_id)
+		SELECT $1, id FROM permission
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := q.Exec(ctx, query, roleID, permission); err != nil {
+		return fmt.Errorf("failed to grant permission %q: %w", permission, err)
+	}
+	return nil
+}
+
+func roleUserIDs(ctx context.Context, q querier, roleID int64) ([]int64, error) {
+	var userIDs []int64
+	err := q.QueryRow(ctx, `SELECT COALESCE(array_agg(user_id), '{}') FROM user_roles WHERE role_id = $1`, roleID).Scan(&userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role users: %w", err)
+	}
+	return userIDs, nil
+}
+
+// users.role - последняя назначенная роль из user_roles, без ролей - пустая строка
+func syncUserRoles(ctx context.Context, q querier, userIDs ...int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE users u
+		SET role = COALESCE((
+			SELECT r.name
+			FROM user_roles ur
+			JOIN roles r ON r.id = ur.role_id
+			WHERE ur.user_id = u.id
+			ORDER BY ur.created_at DESC, r.id DESC
+			LIMIT 1
+		), '')
+		WHERE u.id = ANY($1)
+	`
+	if _, err := q.Exec(ctx, query, userIDs); err != nil {
+		return fmt.Errorf("failed to sync users.role: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ SessionRepository = &sessionRepo{}
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *domain.Session) error
+	ListSessions(ctx context.Context, userID int64) ([]*domain.Session, error)
+	GetSessionForToken(ctx context.Context, token string) (*domain.Session, error)
+	TouchSession(ctx context.Context, id string, expiresAt time.Time) error
+	TouchSessionByToken(ctx context.Context, token string) error
+	DeleteSession(ctx context.Context, userID int64, id string) error
+	DeleteOtherSessions(ctx context.Context, userID int64, keepID string) (int64, error)
+}
+
+type sessionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepo(db *pgxpool.Pool) SessionRepository {
+	return &sessionRepo{
+		db: db,
+	}
+}
+
+func (r *sessionRepo) CreateSession(ctx context.Context, session *domain.Session) error {
+	const op = "repository.postgres.CreateSession"
+
+	query := `
+		INSERT INTO sessions (id, user_id, user_agent, client_ip, expires_at, client_id)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		RETURNING created_at, last_used_at
+	`
+
+	err := r.db.QueryRow(ctx, query, session.ID, session.UserID, session.UserAgent, session.ClientIP, session.ExpiresAt, session.ClientID).Scan(
+		&session.CreatedAt,
+		&session.LastUsedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepo) ListSessions(ctx context.Context, userID int64) ([]*domain.Session, error) {
+	const op = "repository.postgres.ListSessions"
+
+	query := `
+		SELECT id::text, user_id, user_agent, client_ip, COALESCE(client_id, ''), created_at, last_used_at, expires_at
+		FROM sessions
+		WHERE user_id = $1 AND expires_at > now()
+		ORDER BY last_used_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var sessions []*domain.Session
+	for rows.Next() {
+		var s domain.Session
+		err := rows.Scan(
+			&s.ID,
+			&s.UserID,
+			&s.UserAgent,
+			&s.ClientIP,
+			&s.ClientID,
+			&s.CreatedAt,
+			&s.LastUsedAt,
+			&s.ExpiresAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		sessions = append(sessions, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// сессия, к которой привязан access токен
+func (r *sessionRepo) GetSessionForToken(ctx context.Context, token string) (*domain.Session, error) {
+	const op = "repository.postgres.GetSessionForToken"
+
+	tokenHash := sha256.Sum256([]byte(token))
+
+	query := `
+		SELECT s.id::text, s.user_id, s.user_agent, s.client_ip, COALESCE(s.client_id, ''), s.created_at, s.last_used_at, s.expires_at
+		FROM sessions s
+		INNER JOIN tokens t ON s.id = t.session_id
+		WHERE t.token_hash = $1 AND t.expiry > now()
+	`
+
+	var s domain.Session
+	err := r.db.QueryRow(ctx, query, tokenHash[:]).Scan(
+		&s.ID,
+		&s.UserID,
+		&s.UserAgent,
+		&s.ClientIP,
+		&s.ClientID,
+		&s.CreatedAt,
+		&s.LastUsedAt,
+		&s.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrSessionNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &s, nil
+}
+
+// продлеваем сессию при обмене refresh токена
+func (r *sessionRepo) TouchSession(ctx context.Context, id string, expiresAt time.Time) error {
+	const op = "repository.postgres.TouchSession"
+
+	query := `UPDATE sessions SET last_used_at = now(), expires_at = $2 WHERE id = $1`
+
+	if _, err := r.db.Exec(ctx, query, id, expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// обновляем last_used_at не чаще раза в минуту, чтобы не писать в базу на каждый запрос
+func (r *sessionRepo) TouchSessionByToken(ctx context.Context, token string) error {
+	const op = "repository.postgres.TouchSessionByToken"
+
+	tokenHash := sha256.Sum256([]byte(token))
+
+	query := `
+		UPDATE sessions s
+		SET last_used_at = now()
+		FROM tokens t
+		WHERE t.token_hash = $1
+		  AND s.id = t.session_id
+		  AND s.last_used_at < now() - INTERVAL '1 minute'
+	`
+
+	if _, err := r.db.Exec(ctx, query, tokenHash[:]); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// токены сессии удаляются каскадно
+func (r *sessionRepo) DeleteSession(ctx context.Context, userID int64, id string) error {
+	const op = "repository.postgres.DeleteSession"
+
+	query := `DELETE FROM sessions WHERE id::text = $1 AND user_id = $2`
+
+	tag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrSessionNotFound)
+	}
+
+	return nil
+}
+
+func (r *sessionRepo) DeleteOtherSessions(ctx context.Context, userID int64, keepID string) (int64, error) {
+	const op = "repository.postgres.DeleteOtherSessions"
+
+	query := `DELETE FROM sessions WHERE user_id = $1 AND id::text <> $2`
+
+	tag, err := r.db.Exec(ctx, query, userID, keepID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -6,11 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/Iowel/app-auth-service/internal/domain"
 	"github.com/Iowel/app-auth-service/pkg/pb"
 
-	"github.com/jackc/pgx"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -22,15 +24,15 @@ func NewTokenRepository(db *pgxpool.Pool) *TokenRepository {
 	return &TokenRepository{Db: db}
 }
 
-func (m *TokenRepository) InsertToken(t *pb.Token, u *pb.User) error {
+func (m *TokenRepository) InsertToken(t *pb.Token, u *pb.User, sessionID, clientID string) error {
 	// Контекст с таймаутом 3 секунды
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	// Удаляем все предыдущие токены для пользователя
-	const deleteStmt = `DELETE FROM tokens WHERE user_id = $1`
+	// Удаляем предыдущие токены этой сессии, остальные девайсы не трогаем
+	const deleteStmt = `DELETE FROM tokens WHERE session_id = $1`
 
-	if _, err := m.Db.Exec(ctx, deleteStmt, u.Id); err != nil {
+	if _, err := m.Db.Exec(ctx, deleteStmt, sessionID); err != nil {
 		return fmt.Errorf("failed to delete existing tokens: %w", err)
 	}
 
@@ -43,9 +45,12 @@ func (m *TokenRepository) InsertToken(t *pb.Token, u *pb.User) error {
 			token_hash,
 			expiry,
 			created_at,
-			updated_at
+			updated_at,
+			session_id,
+			scope,
+			client_id
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''))
 	`
 	_, err := m.Db.Exec(ctx, insertStmt,
 		u.Id,
@@ -55,6 +60,9 @@ func (m *TokenRepository) InsertToken(t *pb.Token, u *pb.User) error {
 		t.Expiry.AsTime(),
 		time.Now(),
 		time.Now(),
+		sessionID,
+		t.Scope,
+		clientID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert new token: %w", err)
@@ -63,18 +71,29 @@ func (m *TokenRepository) InsertToken(t *pb.Token, u *pb.User) error {
 }
 
 func (m *TokenRepository) GetUserForToken(token string) (*pb.User, error) {
+	info, err := m.GetTokenInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return info.User, nil
+}
+
+// данные токена вместе с его владельцем
+func (m *TokenRepository) GetTokenInfo(token string) (*domain.TokenInfo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
 	// хншируем токен
 	tokenHash := sha256.Sum256([]byte(token))
 
-
 	var user pb.User
+	var info domain.TokenInfo
 
 	query := `
 		SELECT
-			u.id, u.name, u.email, u.role
+			u.id, u.name, u.email, u.role, u.is_email_verified, u.app_id,
+			t.scope, t.expiry, t.created_at, COALESCE(t.session_id::text, ''), COALESCE(t.client_id, '')
 		FROM
 			users u
 		INNER JOIN tokens t ON u.id = t.user_id
@@ -87,16 +106,175 @@ func (m *TokenRepository) GetUserForToken(token string) (*pb.User, error) {
 		&user.Name,
 		&user.Email,
 		&user.Role,
+		&user.Isemailverified,
+		&user.AppId,
+		&info.Scope,
+		&info.Expiry,
+		&info.IssuedAt,
+		&info.SessionID,
+		&info.ClientID,
 	)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			log.Println("Token not found or expired")
-			return nil, nil
+			return nil, domain.ErrTokenNotFound
 		}
 		log.Println("DB error:", err)
 		return nil, err
 	}
 
-	return &user, nil
+	info.User = &user
+	return &info, nil
+}
+
+func (m *TokenRepository) DeleteToken(token string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tokenHash := sha256.Sum256([]byte(token))
+
+	const deleteStmt = `DELETE FROM tokens WHERE token_hash = $1`
+
+	if _, err := m.Db.Exec(ctx, deleteStmt, tokenHash[:]); err != nil {
+		return fmt.Errorf("failed to delete token: %w", err)
+	}
+	return nil
+}
+
+// scope в refresh токене - это права, которые получит следующий access токен
+func (m *TokenRepository) InsertRefreshToken(t *pb.Token, familyID, sessionID, clientID, scope string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	const insertStmt = `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expiry, session_id, client_id, scope)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+	`
+	_, err := m.Db.Exec(ctx, insertStmt, t.Userid, familyID, t.Hash, t.Expiry.AsTime(), sessionID, clientID, scope)
+	if err != nil {
+		return fmt.Errorf("failed to insert refresh token: %w", err)
+	}
+	return nil
+}
+
+func (m *TokenRepository) GetRefreshToken(token string) (*domain.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tokenHash := sha256.Sum256([]byte(token))
+
+	query := `
+		SELECT id, user_id, family_id::text, COALESCE(session_id::text, ''), COALESCE(client_id, ''), scope, token_hash, expiry, is_used, is_revoked, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	var rt domain.RefreshToken
+	err := m.Db.QueryRow(ctx, query, tokenHash[:]).Scan(
+		&rt.ID,
+		&rt.UserID,
+		&rt.FamilyID,
+		&rt.SessionID,
+		&rt.ClientID,
+		&rt.Scope,
+		&rt.Hash,
+		&rt.Expiry,
+		&rt.IsUsed,
+		&rt.IsRevoked,
+		&rt.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return &rt, nil
+}
+
+// помечаем токен использованным, false - если его уже кто-то использовал до нас
+func (m *TokenRepository) MarkRefreshTokenUsed(id int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	const updateStmt = `
+		UPDATE refresh_tokens
+		SET is_used = TRUE
+		WHERE id = $1 AND is_used = FALSE AND is_revoked = FALSE
+	`
+	tag, err := m.Db.Exec(ctx, updateStmt, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark refresh token as used: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// отзываем всю цепочку refresh токенов, выросшую из одного логина
+func (m *TokenRepository) RevokeRefreshTokenFamily(familyID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	const updateStmt = `UPDATE refresh_tokens SET is_revoked = TRUE WHERE family_id = $1`
+
+	if _, err := m.Db.Exec(ctx, updateStmt, familyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+// токен client_credentials: привязан к клиенту, а не к юзверю
+func (m *TokenRepository) InsertServiceToken(t *pb.Token, clientID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	const insertStmt = `
+		INSERT INTO service_tokens (token_hash, client_id, scope, expiry)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := m.Db.Exec(ctx, insertStmt, t.Hash, clientID, t.Scope, t.Expiry.AsTime())
+	if err != nil {
+		return fmt.Errorf("failed to insert service token: %w", err)
+	}
+	return nil
+}
+
+// действующий токен сервиса; клиент, которого разжаловали из машинных, токенами больше не пользуется
+func (m *TokenRepository) GetServiceTokenInfo(token string) (*domain.TokenInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tokenHash := sha256.Sum256([]byte(token))
+
+	query := `
+		SELECT c.client_id, c.name, t.scope, t.expiry, t.created_at
+		FROM service_tokens t
+		INNER JOIN oauth_clients c ON c.client_id = t.client_id
+		WHERE t.token_hash = $1 AND t.expiry > now() AND c.is_service = TRUE
+	`
+
+	var service domain.ServicePrincipal
+	var info domain.TokenInfo
+
+	err := m.Db.QueryRow(ctx, query, tokenHash[:]).Scan(
+		&service.ClientID,
+		&service.Name,
+		&info.Scope,
+		&info.Expiry,
+		&info.IssuedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get service token: %w", err)
+	}
+
+	service.Scopes = strings.Fields(info.Scope)
+	info.ClientID = service.ClientID
+	info.Scopes = service.Scopes
+	info.Service = &service
+
+	return &info, nil
 }
@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ TwoFactorRepository = &twoFactorRepo{}
+
+type TwoFactorRepository interface {
+	UpsertTOTP(ctx context.Context, userID int64, secret []byte) error
+	GetTOTP(ctx context.Context, userID int64) (*domain.UserTOTP, error)
+	ConfirmTOTPTx(ctx context.Context, userID int64, step int64, recoveryCodeHashes [][]byte) error
+	UseTOTPStep(ctx context.Context, userID int64, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash []byte) (bool, error)
+	DeleteTwoFactorTx(ctx context.Context, userID int64) error
+
+	CreateChallenge(ctx context.Context, challenge *domain.TwoFactorChallenge) error
+	ClaimChallengeAttempt(ctx context.Context, tokenHash []byte, maxAttempts int) (*domain.TwoFactorChallenge, error)
+	ConsumeChallenge(ctx context.Context, id int64) (bool, error)
+}
+
+type twoFactorRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTwoFactorRepo(db *pgxpool.Pool) TwoFactorRepository {
+	return &twoFactorRepo{
+		db: db,
+	}
+}
+
+// неподтвержденный секрет перезаписываем, подтвержденный не трогаем
+func (r *twoFactorRepo) UpsertTOTP(ctx context.Context, userID int64, secret []byte) error {
+	const op = "repository.postgres.UpsertTOTP"
+
+	query := `
+		INSERT INTO user_totp (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, created_at = now()
+		WHERE user_totp.is_confirmed = FALSE
+	`
+
+	tag, err := r.db.Exec(ctx, query, userID, secret)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (r *twoFactorRepo) GetTOTP(ctx context.Context, userID int64) (*domain.UserTOTP, error) {
+	const op = "repository.postgres.GetTOTP"
+
+	query := `
+		SELECT user_id, secret, is_confirmed, last_used_step, created_at, confirmed_at
+		FROM user_totp
+		WHERE user_id = $1
+	`
+
+	var t domain.UserTOTP
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&t.UserID,
+		&t.Secret,
+		&t.IsConfirmed,
+		&t.LastUsedStep,
+		&t.CreatedAt,
+		&t.ConfirmedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTwoFactorNotEnabled
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &t, nil
+}
+
+// включаем 2FA и заменяем recovery коды одной транзакцией
+func (r *twoFactorRepo) ConfirmTOTPTx(ctx context.Context, userID int64, step int64, recoveryCodeHashes [][]byte) error {
+	const op = "repository.postgres.ConfirmTOTPTx"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	confirmQuery := `
+		UPDATE user_totp
+		SET is_confirmed = TRUE, confirmed_at = now(), last_used_step = $2
+		WHERE user_id = $1 AND is_confirmed = FALSE
+	`
+	tag, err := tx.Exec(ctx, confirmQuery, userID, step)
+	if err != nil {
+		return fmt.Errorf("%s: failed to confirm totp: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("%s: failed to delete recovery codes: %w", op, err)
+	}
+
+	for _, hash := range recoveryCodeHashes {
+		_, err := tx.Exec(ctx, `INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash)
+		if err != nil {
+			return fmt.Errorf("%s: failed to insert recovery code: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	return nil
+}
+
+// false - код этого или более позднего шага уже принимали
+func (r *twoFactorRepo) UseTOTPStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	const op = "repository.postgres.UseTOTPStep"
+
+	query := `
+		UPDATE user_totp
+		SET last_used_step = $2
+		WHERE user_id = $1 AND is_confirmed = TRUE AND last_used_step < $2
+	`
+
+	tag, err := r.db.Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *twoFactorRepo) UseRecoveryCode(ctx context.Context, userID int64, codeHash []byte) (bool, error) {
+	const op = "repository.postgres.UseRecoveryCode"
+
+	query := `
+		UPDATE user_recovery_codes
+		SET used_at = now()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	tag, err := r.db.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *twoFactorRepo) DeleteTwoFactorTx(ctx context.Context, userID int64) error {
+	const op = "repository.postgres.DeleteTwoFactorTx"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("%s: failed to delete recovery codes: %w", op, err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("%s: failed to delete totp: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *twoFactorRepo) CreateChallenge(ctx context.Context, challenge *domain.TwoFactorChallenge) error {
+	const op = "repository.postgres.CreateChallenge"
+
+	query := `
+		INSERT INTO two_factor_challenges (token_hash, user_id, user_agent, client_ip, expired_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		challenge.Hash,
+		challenge.UserID,
+		challenge.UserAgent,
+		challenge.ClientIP,
+		challenge.ExpiredAt,
+	).Scan(&challenge.ID, &challenge.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// попытку списываем до проверки кода одним запросом: параллельные запросы не проскочат мимо лимита
+func (r *twoFactorRepo) ClaimChallengeAttempt(ctx context.Context, tokenHash []byte, maxAttempts int) (*domain.TwoFactorChallenge, error) {
+	const op = "repository.postgres.ClaimChallengeAttempt"
+
+	query := `
+		UPDATE two_factor_challenges
+		SET attempts = attempts + 1
+		WHERE token_hash = $1
+		  AND attempts < $2
+		  AND is_used = FALSE
+		  AND expired_at > now()
+		RETURNING id, token_hash, user_id, user_agent, client_ip, attempts, is_used, created_at, expired_at
+	`
+
+	var c domain.TwoFactorChallenge
+	err := r.db.QueryRow(ctx, query, tokenHash, maxAttempts).Scan(
+		&c.ID,
+		&c.Hash,
+		&c.UserID,
+		&c.UserAgent,
+		&c.ClientIP,
+		&c.Attempts,
+		&c.IsUsed,
+		&c.CreatedAt,
+		&c.ExpiredAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidTwoFactorChallenge
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &c, nil
+}
+
+// challenge одноразовый: false - его уже обменяли параллельным запросом
+func (r *twoFactorRepo) ConsumeChallenge(ctx context.Context, id int64) (bool, error) {
+	const op = "repository.postgres.ConsumeChallenge"
+
+	tag, err := r.db.Exec(ctx, `UPDATE two_factor_challenges SET is_used = TRUE WHERE id = $1 AND is_used = FALSE`, id)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
@@ -9,8 +9,8 @@ import (
 	"github.com/Iowel/app-auth-service/internal/domain"
 	"github.com/Iowel/app-auth-service/pkg/pb"
 
-	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -18,10 +18,12 @@ import (
 var _ UserRepository = &userRepo{}
 
 type UserRepository interface {
-	CreateUser(email, password, name string) (*pb.User, error)
-	GetUserByEmail(email string) (*pb.User, error)
+	GetUserByEmail(appID int64, email string) (*pb.User, error)
+	GetInternalUserByEmail(ctx context.Context, appID int64, email string) (*pb.InternalUser, error)
+	GetUserByID(ctx context.Context, id int64) (*pb.User, error)
 	CreateUserTx(ctx context.Context, arg domain.CreateUserTxParams) (domain.CreateUserTxResult, error)
 	GetUserByName(ctx context.Context, name string) (*pb.User, error)
+	UpdateUserTx(ctx context.Context, arg domain.UpdateUserTxParams) (domain.UpdateUserTxResult, error)
 
 	CreateProfile(profile *domain.Profile) error
 	GetProfile(id int) (*domain.Profile, error)
@@ -39,67 +41,124 @@ func NewUserRepo(db *pgxpool.Pool) UserRepository {
 	}
 }
 
-func (r *userRepo) CreateUser(email, password, name string) (*pb.User, error) {
+func (u *userRepo) GetUserByEmail(appID int64, email string) (*pb.User, error) {
+	query := `SELECT
+		id, email, name, role, avatar, created_at, updated_at, is_email_verified, app_id
+		FROM
+			users
+		WHERE
+			app_id = $1 AND email = $2
+		`
+
+	var user pb.User
+	var createdAt time.Time
+	var updatedAt time.Time
+
+	err := u.db.QueryRow(context.Background(), query, appID, email).Scan(
+		&user.Id,
+		&user.Email,
+		&user.Name,
+		&user.Role,
+		&user.Avatar,
+		&createdAt,
+		&updatedAt,
+		&user.Isemailverified,
+		&user.AppId,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch user by email: %w", err)
+	}
+
+	// клнвертируем время в timestamppb.Timestamp
+	user.CreatedAt = timestamppb.New(createdAt)
+	user.UpdatedAt = timestamppb.New(updatedAt)
+
+	return &user, nil
+}
+
+// единственное место, где читается хеш пароля: он нужен только для проверки при логине
+func (u *userRepo) GetInternalUserByEmail(ctx context.Context, appID int64, email string) (*pb.InternalUser, error) {
+	const op = "repository.postgres.GetInternalUserByEmail"
+
 	query := `
-		INSERT INTO users (email, password, name)
-		VALUES ($1, $2, $3)
-		RETURNING id, created_at, updated_at
+	SELECT
+		id, email, name, role, avatar, created_at, updated_at, is_email_verified, app_id, password
+	FROM
+		users
+	WHERE
+		app_id = $1 AND email = $2
 	`
 
-	user := &pb.User{}
+	var user pb.User
+	var passwordHash string
 	var createdAt time.Time
 	var updatedAt time.Time
 
-	err := r.db.QueryRow(context.Background(), query, email, password, name).Scan(
+	err := u.db.QueryRow(ctx, query, appID, email).Scan(
 		&user.Id,
+		&user.Email,
+		&user.Name,
+		&user.Role,
+		&user.Avatar,
 		&createdAt,
 		&updatedAt,
+		&user.Isemailverified,
+		&user.AppId,
+		&passwordHash,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %v", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	user.Email = email
-	user.Name = name
-	user.Password = password
 	user.CreatedAt = timestamppb.New(createdAt)
 	user.UpdatedAt = timestamppb.New(updatedAt)
 
-	return user, nil
+	return &pb.InternalUser{
+		User:         &user,
+		PasswordHash: passwordHash,
+	}, nil
 }
 
-func (u *userRepo) GetUserByEmail(email string) (*pb.User, error) {
-	query := `SELECT
-		id, email, name, password, role, avatar, created_at, updated_at, is_email_verified
-		FROM
-			users
-		WHERE
-			email = $1
-		`
+func (u *userRepo) GetUserByID(ctx context.Context, id int64) (*pb.User, error) {
+	const op = "repository.postgres.GetUserByID"
+
+	query := `
+	SELECT
+		id, email, name, role, avatar, created_at, updated_at, is_email_verified, app_id
+	FROM
+		users
+	WHERE
+		id = $1
+	`
 
 	var user pb.User
 	var createdAt time.Time
 	var updatedAt time.Time
 
-	err := u.db.QueryRow(context.Background(), query, email).Scan(
+	err := u.db.QueryRow(ctx, query, id).Scan(
 		&user.Id,
 		&user.Email,
 		&user.Name,
-		&user.Password,
 		&user.Role,
 		&user.Avatar,
 		&createdAt,
 		&updatedAt,
 		&user.Isemailverified,
+		&user.AppId,
 	)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, domain.ErrUserNotFound
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrUserNotFound)
 		}
-		return nil, fmt.Errorf("failed to fetch user by email: %w", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// клнвертируем время в timestamppb.Timestamp
 	user.CreatedAt = timestamppb.New(createdAt)
 	user.UpdatedAt = timestamppb.New(updatedAt)
 
@@ -118,15 +177,15 @@ func (r *userRepo) CreateUserTx(ctx context.Context, arg domain.CreateUserTxPara
 	defer tx.Rollback(ctx)
 
 	query := `
-    INSERT INTO users (email, password, name)
-    VALUES ($1, $2, $3)
+    INSERT INTO users (email, password, name, app_id)
+    VALUES ($1, $2, $3, $4)
     RETURNING id, role, avatar, is_email_verified, created_at, updated_at 
 `
 
 	var user pb.User
 	var createdAt, updatedAt time.Time
 
-	err = tx.QueryRow(ctx, query, arg.User.Email, arg.User.Password, arg.User.Name).Scan(
+	err = tx.QueryRow(ctx, query, arg.User.Email, arg.PasswordHash, arg.User.Name, arg.User.AppId).Scan(
 		&user.Id,
 		&user.Role,
 		&user.Avatar,
@@ -147,7 +206,7 @@ func (r *userRepo) CreateUserTx(ctx context.Context, arg domain.CreateUserTxPara
 
 	user.Email = arg.User.Email
 	user.Name = arg.User.Name
-	user.Password = arg.User.Password
+	user.AppId = arg.User.AppId
 	user.CreatedAt = timestamppb.New(createdAt)
 	user.UpdatedAt = timestamppb.New(updatedAt)
 
@@ -166,12 +225,114 @@ func (r *userRepo) CreateUserTx(ctx context.Context, arg domain.CreateUserTxPara
 	return result, nil
 }
 
+func (r *userRepo) UpdateUserTx(ctx context.Context, arg domain.UpdateUserTxParams) (domain.UpdateUserTxResult, error) {
+	const op = "repository.postgres.UpdateUserTx"
+
+	var result domain.UpdateUserTxResult
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return result, fmt.Errorf("%s: begin tx failed: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// nil-поля не трогаем, оставляем текущие значения
+	query := `
+		UPDATE users
+		SET
+			name = COALESCE($1, name),
+			password = COALESCE($2, password),
+			email = COALESCE($3, email),
+			is_email_verified = COALESCE($4, is_email_verified),
+			updated_at = now()
+		WHERE id = $5
+		RETURNING id, email, name, role, avatar, is_email_verified, app_id, created_at, updated_at;
+	`
+
+	var user pb.User
+	var createdAt, updatedAt time.Time
+
+	err = tx.QueryRow(ctx, query, arg.Name, arg.Password, arg.Email, arg.IsEmailVerified, arg.ID).Scan(
+		&user.Id,
+		&user.Email,
+		&user.Name,
+		&user.Role,
+		&user.Avatar,
+		&user.Isemailverified,
+		&user.AppId,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return result, fmt.Errorf("%s: %w", op, domain.ErrUserNotFound)
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return result, fmt.Errorf("%s: %w", op, domain.ErrUserExists)
+		}
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user.CreatedAt = timestamppb.New(createdAt)
+	user.UpdatedAt = timestamppb.New(updatedAt)
+
+	// ссылки, отправленные на старый адрес, не должны подтверждать новый
+	if arg.Email != nil {
+		query := `UPDATE verify_emails SET is_used = TRUE WHERE user_id = $1 AND is_used = FALSE`
+		if _, err := tx.Exec(ctx, query, arg.ID); err != nil {
+			return result, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// новый пароль разлогинивает все остальные девайсы: токены и refresh семьи сессий удаляются каскадно
+	if arg.Password != nil {
+		if err := revokeOtherSessionsTx(ctx, tx, arg.ID, arg.KeepSessionID); err != nil {
+			return result, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// новый email нужно подтвердить заново, письмо ставим в очередь в рамках транзакции
+	if arg.Email != nil && arg.AfterEmailChange != nil {
+		if err := arg.AfterEmailChange(&user); err != nil {
+			return result, fmt.Errorf("%s: after email change callback failed: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return result, fmt.Errorf("%s: commit failed: %w", op, err)
+	}
+
+	result.User = &user
+	return result, nil
+}
+
+func revokeOtherSessionsTx(ctx context.Context, tx pgx.Tx, userID int64, keepSessionID string) error {
+	query := `DELETE FROM sessions WHERE user_id = $1 AND id::text <> $2`
+	if _, err := tx.Exec(ctx, query, userID, keepSessionID); err != nil {
+		return err
+	}
+
+	// токены, выданные до появления сессий, к сессии не привязаны и каскадом не удаляются
+	query = `DELETE FROM tokens WHERE user_id = $1 AND session_id IS NULL AND scope = 'authentication'`
+	if _, err := tx.Exec(ctx, query, userID); err != nil {
+		return err
+	}
+
+	query = `UPDATE refresh_tokens SET is_revoked = TRUE WHERE user_id = $1 AND session_id IS NULL`
+	if _, err := tx.Exec(ctx, query, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (u *userRepo) GetUserByName(ctx context.Context, name string) (*pb.User, error) {
 	const op = "storage.postgres.GetUserByName"
 
 	query := `
 	SELECT
-		id, email, name, password, created_at
+		id, email, name, created_at
 	FROM
 		users
 	WHERE
@@ -187,7 +348,6 @@ func (u *userRepo) GetUserByName(ctx context.Context, name string) (*pb.User, er
 		&user.Id,
 		&user.Email,
 		&user.Name,
-		&user.Password,
 		&createdAt,
 	)
 
@@ -240,6 +400,9 @@ func (r *userRepo) GetProfile(id int) (*domain.Profile, error) {
 		&profile.Wallet,
 	)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrProfileNotFound
+		}
 		return nil, err
 	}
 	return &profile, nil
@@ -10,11 +10,14 @@ import (
 	"time"
 
 	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/pkg/jwtauth"
 	"github.com/Iowel/app-auth-service/internal/repository/postgres"
 	"github.com/Iowel/app-auth-service/pkg/cache"
 	"github.com/Iowel/app-auth-service/pkg/eventbus"
 	"github.com/Iowel/app-auth-service/pkg/pb"
+	"github.com/Iowel/app-auth-service/pkg/util"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/metadata"
 )
@@ -22,73 +25,133 @@ import (
 var _ IAuthService = &authService{}
 
 type IAuthService interface {
-	Register(email, password, name string) (*pb.User, error)
-	Login(email, password string) (*pb.Token, error)
+	Login(ctx context.Context, params domain.LoginParams) (*domain.LoginResult, error)
+	IssueTokens(ctx context.Context, user *pb.User, params domain.IssueTokensParams) (*domain.AuthTokens, error)
+	RefreshToken(ctx context.Context, refreshToken, clientID string) (*domain.AuthTokens, error)
 	RegisterTx(ctx context.Context, params domain.CreateUserTxParams) (*domain.CreateUserTxResult, error)
 	AuthorizeUser(ctx context.Context) (*pb.User, error)
+	AuthenticateToken(ctx context.Context) (*domain.TokenInfo, error)
+	Logout(ctx context.Context) error
+	IntrospectToken(ctx context.Context, token string) (*domain.TokenIntrospection, error)
+	UpdateUserTx(ctx context.Context, params domain.UpdateUserTxParams) (*domain.UpdateUserTxResult, error)
+
+	ListSessions(ctx context.Context) ([]*domain.Session, string, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeAllOtherSessions(ctx context.Context) (int64, error)
+
+	CreatePersonalAccessToken(ctx context.Context, params domain.CreatePersonalAccessTokenParams) (*domain.CreatedPersonalAccessToken, error)
+	ListPersonalAccessTokens(ctx context.Context) ([]*domain.PersonalAccessToken, error)
+	RevokePersonalAccessToken(ctx context.Context, id int64) error
+
+	EnrollTwoFactor(ctx context.Context) (*domain.TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, code string) ([]string, error)
+	VerifyTwoFactor(ctx context.Context, params domain.VerifyTwoFactorParams) (*domain.AuthTokens, error)
+	DisableTwoFactor(ctx context.Context, code string) error
+
+	RequestPasswordReset(ctx context.Context, params domain.RequestPasswordResetParams) error
+	ResetPassword(ctx context.Context, params domain.ResetPasswordParams) error
+
+	RequestMagicLink(ctx context.Context, params domain.RequestMagicLinkParams) error
+	ConsumeMagicLink(ctx context.Context, params domain.ConsumeMagicLinkParams) (*domain.LoginResult, error)
+
+	UnlockAccount(ctx context.Context, appID int64, email string) error
 
 	CreateProfile(profile *domain.Profile) error
 	GetStatusIDByName(ctx context.Context, name string) (int, error)
 }
 
 type authService struct {
-	userRepo  postgres.UserRepository
-	tokenRepo *postgres.TokenRepository
-	cache     cache.IPostCache
-	eventbus  *eventbus.EventBus
+	userRepo      postgres.UserRepository
+	appRepo       postgres.AppRepository
+	tokenRepo     *postgres.TokenRepository
+	sessionRepo   postgres.SessionRepository
+	patRepo       postgres.PersonalAccessTokenRepository
+	twoFactorRepo postgres.TwoFactorRepository
+	resetRepo     postgres.PasswordResetRepository
+	mailRepo      postgres.EmailRepositoryI
+	cache         cache.IPostCache
+	eventbus      *eventbus.EventBus
+
+	// неудачные логины и блокировки
+	attempts cache.ILoginAttempts
+	lockout  LockoutPolicy
+
+	// если задан - access токены выдаются в виде подписанных JWT
+	signer *jwtauth.Manager
+
+	// ключ шифрования TOTP секретов и имя сервиса в приложении-аутентификаторе
+	secret     string
+	totpIssuer string
 }
 
-func NewAuthService(u postgres.UserRepository, tokenRepo *postgres.TokenRepository, cache cache.IPostCache, e *eventbus.EventBus) IAuthService {
-	return &authService{
-		userRepo:  u,
-		tokenRepo: tokenRepo,
-		cache:     cache,
-		eventbus:  e,
-	}
-}
+// остальные зависимости одной структурой - в длинном списке аргументов одного типа легко перепутать порядок
+type AuthServiceDeps struct {
+	AppRepo       postgres.AppRepository
+	SessionRepo   postgres.SessionRepository
+	PATRepo       postgres.PersonalAccessTokenRepository
+	TwoFactorRepo postgres.TwoFactorRepository
+	ResetRepo     postgres.PasswordResetRepository
+	MailRepo      postgres.EmailRepositoryI
 
-func (a *authService) Register(email, password, name string) (*pb.User, error) {
-	const op = "service.auth.Register"
+	Attempts cache.ILoginAttempts
+	Lockout  LockoutPolicy
 
-	existedUser, _ := a.userRepo.GetUserByEmail(email)
-	if existedUser != nil {
-		return nil, domain.ErrUserExists
-	}
+	// nil - access токены непрозрачные
+	Signer *jwtauth.Manager
 
-	// create password
-	hashPass, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return nil, err
-	}
+	Secret     string
+	TOTPIssuer string
+}
 
-	// create user
-	createdUser, err := a.userRepo.CreateUser(email, string(hashPass), name)
-	if err != nil {
-		return nil, err
+func NewAuthService(u postgres.UserRepository, tokenRepo *postgres.TokenRepository, cache cache.IPostCache, e *eventbus.EventBus, deps AuthServiceDeps) IAuthService {
+	return &authService{
+		userRepo:      u,
+		appRepo:       deps.AppRepo,
+		tokenRepo:     tokenRepo,
+		sessionRepo:   deps.SessionRepo,
+		patRepo:       deps.PATRepo,
+		twoFactorRepo: deps.TwoFactorRepo,
+		resetRepo:     deps.ResetRepo,
+		mailRepo:      deps.MailRepo,
+		cache:         cache,
+		eventbus:      e,
+		attempts:      deps.Attempts,
+		lockout:       deps.Lockout.withDefaults(),
+		signer:        deps.Signer,
+		secret:        deps.Secret,
+		totpIssuer:    deps.TOTPIssuer,
 	}
-
-	return createdUser, nil
 }
 
 func (a *authService) RegisterTx(ctx context.Context, params domain.CreateUserTxParams) (*domain.CreateUserTxResult, error) {
 	const op = "service.auth.RegisterTx"
 
-	// проверяем на существование юзера
-	existedUser, _ := a.userRepo.GetUserByEmail(params.User.Email)
+	err := a.checkApp(ctx, params.User.AppId)
+	if err != nil {
+		return nil, err
+	}
+
+	// проверяем на существование юзера в этом приложении
+	existedUser, _ := a.userRepo.GetUserByEmail(params.User.AppId, params.User.Email)
 	if existedUser != nil {
 		return nil, domain.ErrUserExists
 	}
 
 	// генерим пароль
-	hashPass, err := bcrypt.GenerateFromPassword([]byte(params.User.Password), bcrypt.DefaultCost)
+	hashPass, err := bcrypt.GenerateFromPassword([]byte(params.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
 	}
-	params.User.Password = string(hashPass)
+	params.PasswordHash = string(hashPass)
+	params.Password = ""
 
 	// делаем юзверя
 	user, err := a.userRepo.CreateUserTx(ctx, params)
 	if err != nil {
+		// параллельная регистрация с тем же email успела раньше
+		if errors.Is(err, domain.ErrUserExists) {
+			return nil, domain.ErrUserExists
+		}
 		return nil, domain.ErrWrongCredentials
 	}
 
@@ -100,24 +163,38 @@ func (a *authService) RegisterTx(ctx context.Context, params domain.CreateUserTx
 		},
 	})
 
-
 	return &user, nil
 }
 
-func (a *authService) Login(email, password string) (*pb.Token, error) {
+func (a *authService) Login(ctx context.Context, params domain.LoginParams) (*domain.LoginResult, error) {
 	const op = "service.auth.Login"
 
+	err := a.checkApp(ctx, params.AppID)
+	if err != nil {
+		return nil, err
+	}
+
+	// после череды неудач ждем или отказываем сразу, не доходя до bcrypt
+	err = a.checkLoginThrottle(ctx, params.AppID, params.Email, params.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
 	// проверяем на наличие юзверя
-	existUser, err := a.userRepo.GetUserByEmail(email)
+	internalUser, err := a.userRepo.GetInternalUserByEmail(ctx, params.AppID, params.Email)
 	if err != nil {
-		log.Printf("GetUserByEmail failed: %s, error: %s", op, err)
+		log.Printf("GetInternalUserByEmail failed: %s, error: %s", op, err)
+		a.registerLoginFailure(ctx, params.AppID, params.Email, params.ClientIP)
 		return nil, domain.ErrWrongCredentials
 	}
+	// дальше работаем только с публичной частью, хеш не должен уйти ни в токены, ни в кэш
+	existUser := internalUser.User
 
 	// проверяем пароль
-	err = bcrypt.CompareHashAndPassword([]byte(existUser.Password), []byte(password))
+	err = bcrypt.CompareHashAndPassword([]byte(internalUser.PasswordHash), []byte(params.Password))
 	if err != nil {
 		log.Printf("Password comparison failed: path: %s, error: %s", op, err)
+		a.registerLoginFailure(ctx, params.AppID, params.Email, params.ClientIP)
 		return nil, domain.ErrWrongCredentials
 	}
 
@@ -126,53 +203,345 @@ func (a *authService) Login(email, password string) (*pb.Token, error) {
 		return nil, domain.Isemailverified
 	}
 
-	// генерим токен
-	token, err := GenerateToken(existUser.Id, 48*time.Hour, ScopeAuthentication)
+	// с включенной 2FA вместо токенов отдаем challenge для VerifyTwoFactor
+	twoFactor, err := a.twoFactorEnabled(ctx, existUser.Id)
 	if err != nil {
-		log.Printf("GenerateToken failed: path: %s, error: %s", op, err)
-		return nil, domain.ErrWrongCredentials
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if twoFactor {
+		challenge, err := a.createTwoFactorChallenge(ctx, existUser, params.UserAgent, params.ClientIP)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return &domain.LoginResult{ChallengeToken: challenge}, nil
 	}
+	// с 2FA счетчик неудач обнулит VerifyTwoFactor, иначе перебор кода не упирался бы в блокировку
+	a.resetLoginFailures(ctx, params.AppID, params.Email)
 
-	// сохрпняем токен в базе
-	err = a.tokenRepo.InsertToken(token, existUser)
+	tokens, err := a.IssueTokens(ctx, existUser, domain.IssueTokensParams{
+		Scope:     ScopeAuthentication,
+		UserAgent: params.UserAgent,
+		ClientIP:  params.ClientIP,
+	})
 	if err != nil {
-		log.Printf("InsertToken failed: %s, error: %s", op, err)
+		log.Printf("IssueTokens failed: path: %s, error: %s", op, err)
 		return nil, domain.ErrWrongCredentials
 	}
 
-	existProfile, err := a.userRepo.GetProfile(int(existUser.Id))
+	return &domain.LoginResult{Tokens: tokens}, nil
+}
+
+// app_id обязателен; неизвестное или выключенное приложение не пускаем
+func (a *authService) checkApp(ctx context.Context, appID int64) error {
+	const op = "service.auth.checkApp"
+
+	if appID <= 0 {
+		return domain.ErrInvalidAppID
+	}
+
+	app, err := a.appRepo.GetApp(ctx, appID)
 	if err != nil {
-		log.Printf("InsertToken failed: %s, error: %s", op, err)
-		return nil, domain.ErrWrongCredentials
+		if errors.Is(err, domain.ErrInvalidAppID) {
+			return domain.ErrInvalidAppID
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !app.IsActive {
+		return domain.ErrInvalidAppID
+	}
+
+	return nil
+}
+
+// новая сессия с парой токенов для юзверя, который уже подтвердил, что это он
+func (a *authService) IssueTokens(ctx context.Context, user *pb.User, params domain.IssueTokensParams) (*domain.AuthTokens, error) {
+	const op = "service.auth.IssueTokens"
+
+	// каждый логин - новая сессия (девайс)
+	session := &domain.Session{
+		ID:        uuid.NewString(),
+		UserID:    user.Id,
+		UserAgent: params.UserAgent,
+		ClientIP:  params.ClientIP,
+		ClientID:  params.ClientID,
+		ExpiresAt: time.Now().Add(RefreshTokenTTL),
+	}
+	err := a.sessionRepo.CreateSession(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// генерим access и refresh токены, логин начинает новую цепочку refresh токенов
+	tokens, err := a.issueTokens(user, tokenGrant{
+		familyID:  uuid.NewString(),
+		sessionID: session.ID,
+		scope:     params.Scope,
+		clientID:  params.ClientID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	// save to redis cache
-	var u = &domain.UserCache{
-		ID:       int(existUser.Id),
-		Email:    existUser.Email,
-		Password: existUser.Password,
-		Name:     existUser.Name,
-		// IsEmailVerified: existUser.Isemailverified,
-		Avatar:    existUser.Avatar,
-		Role:      existUser.Role,
-		Status:    existProfile.Status,
-		Wallet:    &existProfile.Wallet,
-		CreatedAt: existUser.CreatedAt.AsTime(),
-		UpdatedAt: existUser.UpdatedAt.AsTime(),
-	}
-
-	idStr := "user:" + strconv.Itoa(u.ID)
-	a.cache.Set(idStr, u)
+	err = a.cacheUser(user)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
 	go a.eventbus.Publish(eventbus.Event{
 		Type: eventbus.EventType,
 		Data: domain.Stat{
-			UserID:      existUser.Id,
+			UserID:      user.Id,
 			Description: eventbus.EventUserLogin,
 		},
 	})
 
-	return token, nil
+	return tokens, nil
+}
+
+// clientID - OAuth клиент, которому выдавался токен, пустой для наших собственных логинов
+func (a *authService) RefreshToken(ctx context.Context, refreshToken, clientID string) (*domain.AuthTokens, error) {
+	const op = "service.auth.RefreshToken"
+
+	rt, err := a.tokenRepo.GetRefreshToken(refreshToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// токены, выданные до появления сессий, не обмениваем
+	if rt.IsRevoked || rt.SessionID == "" || time.Now().After(rt.Expiry) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	// токен стороннего приложения нельзя обменять от имени другого клиента
+	if rt.ClientID != clientID {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	// токен уже обменивали - значит его украли, отзываем всю цепочку
+	if rt.IsUsed {
+		a.revokeTokenFamily(ctx, rt)
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	// параллельный запрос успел обменять этот же токен раньше нас
+	ok, err := a.tokenRepo.MarkRefreshTokenUsed(rt.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		a.revokeTokenFamily(ctx, rt)
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	user, err := a.userRepo.GetUserByID(ctx, rt.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := a.issueTokens(user, tokenGrant{
+		familyID:  rt.FamilyID,
+		sessionID: rt.SessionID,
+		scope:     rt.Scope,
+		clientID:  rt.ClientID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// сессия живет, пока живет ее refresh токен
+	err = a.sessionRepo.TouchSession(ctx, rt.SessionID, tokens.RefreshToken.Expiry.AsTime())
+	if err != nil {
+		log.Printf("TouchSession failed: %s, error: %s", op, err)
+	}
+
+	return tokens, nil
+}
+
+// на что выдаются токены: цепочка refresh токенов, сессия и права клиента
+type tokenGrant struct {
+	familyID  string
+	sessionID string
+	scope     string
+	clientID  string
+}
+
+// генерим короткоживущий access токен и refresh токен из цепочки grant.familyID
+func (a *authService) issueTokens(user *pb.User, grant tokenGrant) (*domain.AuthTokens, error) {
+	var accessToken *pb.Token
+	var err error
+	if a.signer != nil {
+		accessToken, err = GenerateSignedToken(a.signer, user, AccessTokenTTL, grant.scope, grant.sessionID, grant.clientID)
+	} else {
+		accessToken, err = GenerateToken(user.Id, AccessTokenTTL, grant.scope)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	accessToken.AppId = user.AppId
+
+	// сохраняем токен в базе
+	err = a.tokenRepo.InsertToken(accessToken, user, grant.sessionID, grant.clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := GenerateToken(user.Id, RefreshTokenTTL, ScopeRefresh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	refreshToken.AppId = user.AppId
+
+	// в базе храним только хеш
+	err = a.tokenRepo.InsertRefreshToken(refreshToken, grant.familyID, grant.sessionID, grant.clientID, grant.scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (a *authService) revokeTokenFamily(ctx context.Context, rt *domain.RefreshToken) {
+	const op = "service.auth.revokeTokenFamily"
+
+	log.Printf("refresh token reuse detected: path: %s, user_id: %d, family_id: %s", op, rt.UserID, rt.FamilyID)
+
+	if err := a.tokenRepo.RevokeRefreshTokenFamily(rt.FamilyID); err != nil {
+		log.Printf("RevokeRefreshTokenFamily failed: %s, error: %s", op, err)
+	}
+
+	// удаляем сессию вместе с ее access токеном
+	if err := a.sessionRepo.DeleteSession(ctx, rt.UserID, rt.SessionID); err != nil {
+		log.Printf("DeleteSession failed: %s, error: %s", op, err)
+	}
+}
+
+func (a *authService) UpdateUserTx(ctx context.Context, params domain.UpdateUserTxParams) (*domain.UpdateUserTxResult, error) {
+	const op = "service.auth.UpdateUserTx"
+
+	current, err := a.userRepo.GetUserByID(ctx, params.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// email не поменялся - ничего не переподтверждаем
+	if params.Email != nil && *params.Email == current.Email {
+		params.Email = nil
+	}
+
+	// новый email считаем неподтвержденным
+	if params.Email != nil {
+		verified := false
+		params.IsEmailVerified = &verified
+	}
+
+	// по одному утекшему токену аккаунт не угнать: email и пароль меняем только с текущим паролем
+	if params.Email != nil || params.Password != nil {
+		err = a.checkCurrentPassword(ctx, current, params.CurrentPassword)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if params.Password != nil {
+		params.KeepSessionID = a.currentSessionID(ctx)
+
+		hashPass, err := util.HashPassword(*params.Password)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		params.Password = &hashPass
+	}
+
+	result, err := a.userRepo.UpdateUserTx(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// обновляем юзверя в redis, чтобы другие сервисы не видели старые данные
+	err = a.cacheUser(result.User)
+	if err != nil {
+		log.Printf("cacheUser failed: %s, error: %s", op, err)
+		a.cache.Delete(userCacheKey(result.User.Id))
+	}
+
+	return &result, nil
+}
+
+// неверный пароль считаем как неудачный логин, иначе UpdateUser - способ перебирать пароль без блокировки
+func (a *authService) checkCurrentPassword(ctx context.Context, user *pb.User, password string) error {
+	const op = "service.auth.checkCurrentPassword"
+
+	if password == "" {
+		return domain.ErrCurrentPasswordRequired
+	}
+
+	err := a.checkLoginThrottle(ctx, user.AppId, user.Email, "")
+	if err != nil {
+		return err
+	}
+
+	internalUser, err := a.userRepo.GetInternalUserByEmail(ctx, user.AppId, user.Email)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(internalUser.PasswordHash), []byte(password))
+	if err != nil {
+		a.registerLoginFailure(ctx, user.AppId, user.Email, "")
+		return domain.ErrWrongCredentials
+	}
+
+	return nil
+}
+
+// сессия токена из запроса; у PAT и токенов до появления сессий ее нет
+func (a *authService) currentSessionID(ctx context.Context) string {
+	accessToken, err := accessTokenFromContext(ctx)
+	if err != nil {
+		return ""
+	}
+
+	session, err := a.sessionRepo.GetSessionForToken(ctx, accessToken)
+	if err != nil {
+		return ""
+	}
+
+	return session.ID
+}
+
+// кладем юзверя вместе с данными профиля в redis кэш
+func (a *authService) cacheUser(user *pb.User) error {
+	profile, err := a.userRepo.GetProfile(int(user.Id))
+	if err != nil {
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	var u = &domain.UserCache{
+		ID:    int(user.Id),
+		Email: user.Email,
+		Name:  user.Name,
+		// IsEmailVerified: user.Isemailverified,
+		Avatar:    user.Avatar,
+		Role:      user.Role,
+		Status:    profile.Status,
+		Wallet:    &profile.Wallet,
+		CreatedAt: user.CreatedAt.AsTime(),
+		UpdatedAt: user.UpdatedAt.AsTime(),
+	}
+
+	a.cache.Set(userCacheKey(user.Id), u)
+	return nil
+}
+
+func userCacheKey(id int64) string {
+	return "user:" + strconv.FormatInt(id, 10)
 }
 
 const (
@@ -181,39 +550,132 @@ const (
 )
 
 func (a *authService) AuthorizeUser(ctx context.Context) (*pb.User, error) {
-	const op = "service.auth.AuthorizeUser"
+	info, err := a.AuthenticateToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// токен сервиса годится для VerifyToken, но не для ручек, которые работают с юзверем
+	if info.User == nil {
+		return nil, errors.New("token does not belong to a user")
+	}
+
+	return info.User, nil
+}
+
+// юзверь и права токена из запроса: access токен сессии или personal access token
+func (a *authService) AuthenticateToken(ctx context.Context) (*domain.TokenInfo, error) {
+	const op = "service.auth.AuthenticateToken"
+
+	// интерцептор уже проверил токен этого вызова
+	if info, ok := PrincipalFromContext(ctx); ok {
+		return info, nil
+	}
+
+	accessToken, err := accessTokenFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(accessToken, domain.PersonalAccessTokenPrefix) {
+		return a.authenticatePersonalAccessToken(ctx, accessToken)
+	}
+	if strings.HasPrefix(accessToken, domain.ServiceTokenPrefix) {
+		info, err := a.tokenRepo.GetServiceTokenInfo(accessToken)
+		if err != nil {
+			log.Printf("GetServiceTokenInfo failed: %s, error: %s", op, err)
+			return nil, errors.New("No matching service found for the given token")
+		}
+		return info, nil
+	}
+
+	// подпись проверяем до похода в базу, чтобы не нагружать ее мусорными токенами
+	if a.signer != nil && jwtauth.LooksLikeJWT(accessToken) {
+		if _, err := a.signer.Verify(accessToken); err != nil {
+			log.Printf("jwt verification failed: %s, error: %s", op, err)
+			return nil, errors.New("No matching user found for the given token")
+		}
+	}
+
+	// get the user from tokens table
+	info, err := a.tokenRepo.GetTokenInfo(accessToken)
+	if err != nil {
+		log.Printf("GetTokenInfo failed: %s, error: %s", op, err)
+		return nil, errors.New("No matching user found for the given token")
+	}
+
+	// токены, выданные сторонним приложениям, дают доступ только к тому, на что согласился юзверь
+	if info.Scope != ScopeAuthentication {
+		log.Printf("token scope rejected: %s, scope: %s", op, info.Scope)
+		return nil, errors.New("token is not valid for this operation")
+	}
+	info.Scopes = []string{info.Scope}
 
+	err = a.sessionRepo.TouchSessionByToken(ctx, accessToken)
+	if err != nil {
+		log.Printf("TouchSessionByToken failed: %s, error: %s", op, err)
+	}
+
+	return info, nil
+}
+
+// отзываем токен из запроса вместе с его сессией
+func (a *authService) Logout(ctx context.Context) error {
+	const op = "service.auth.Logout"
+
+	user, err := a.AuthorizeUser(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+
+	accessToken, err := accessTokenFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+
+	// вместе с сессией удаляется и refresh токен, иначе клиент просто получит новый access токен
+	session, err := a.sessionRepo.GetSessionForToken(ctx, accessToken)
+	if err == nil {
+		err = a.sessionRepo.DeleteSession(ctx, user.Id, session.ID)
+		if err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err = a.tokenRepo.DeleteToken(accessToken)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.cache.Delete(userCacheKey(user.Id))
+
+	return nil
+}
+
+// достаем bearer токен из заголовка authorization
+func accessTokenFromContext(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return nil, fmt.Errorf("missing metadata")
+		return "", fmt.Errorf("missing metadata")
 	}
 
 	values := md.Get(authorizationHeader)
 	if len(values) == 0 {
-		return nil, fmt.Errorf("missing authorization header")
+		return "", fmt.Errorf("missing authorization header")
 	}
 	authHeader := values[0]
 	fields := strings.Fields(authHeader)
 	if len(fields) < 2 {
-		return nil, fmt.Errorf("invalid authorization header format")
+		return "", fmt.Errorf("invalid authorization header format")
 	}
 
 	authType := strings.ToLower(fields[0])
 	if authType != authorizationBearer {
-		return nil, fmt.Errorf("unsupported authorization type: %s", authType)
+		return "", fmt.Errorf("unsupported authorization type: %s", authType)
 
 	}
 
-	accessToken := fields[1]
-
-	// get the user from tokens table
-	user, err := a.tokenRepo.GetUserForToken(accessToken)
-	if err != nil {
-		log.Printf("GetUserForToken failed: %s, error: %s", op, err)
-		return nil, errors.New("No matching user found for the given token")
-	}
-
-	return user, nil
+	return fields[1], nil
 }
 
 func (a *authService) CreateProfile(profile *domain.Profile) error {
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/pkg/jwtauth"
+)
+
+const (
+	tokenTypeBearer  = "Bearer"
+	tokenTypeRefresh = "refresh_token"
+)
+
+var inactiveToken = &domain.TokenIntrospection{Active: false}
+
+// token introspection (RFC 7662): неизвестный, истекший или отозванный токен - просто active=false
+func (a *authService) IntrospectToken(ctx context.Context, token string) (*domain.TokenIntrospection, error) {
+	const op = "service.introspect.IntrospectToken"
+
+	if token == "" {
+		return inactiveToken, nil
+	}
+
+	if strings.HasPrefix(token, domain.PersonalAccessTokenPrefix) {
+		return a.introspectPersonalAccessToken(ctx, token)
+	}
+	if strings.HasPrefix(token, domain.ServiceTokenPrefix) {
+		return a.introspectServiceToken(token)
+	}
+
+	if a.signer != nil && jwtauth.LooksLikeJWT(token) {
+		if _, err := a.signer.Verify(token); err != nil {
+			return inactiveToken, nil
+		}
+	}
+
+	info, err := a.tokenRepo.GetTokenInfo(token)
+	if err == nil {
+		return &domain.TokenIntrospection{
+			Active:    true,
+			Sub:       strconv.FormatInt(info.User.Id, 10),
+			Scope:     info.Scope,
+			ClientID:  info.ClientID,
+			Exp:       info.Expiry.Unix(),
+			Iat:       info.IssuedAt.Unix(),
+			Role:      info.User.Role,
+			AppID:     info.User.AppId,
+			Username:  info.User.Email,
+			TokenType: tokenTypeBearer,
+		}, nil
+	}
+	if !errors.Is(err, domain.ErrTokenNotFound) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// не access токен - возможно, refresh
+	rt, err := a.tokenRepo.GetRefreshToken(token)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) {
+			return inactiveToken, nil
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rt.IsUsed || rt.IsRevoked || time.Now().After(rt.Expiry) {
+		return inactiveToken, nil
+	}
+
+	user, err := a.userRepo.GetUserByID(ctx, rt.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return inactiveToken, nil
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &domain.TokenIntrospection{
+		Active:    true,
+		Sub:       strconv.FormatInt(user.Id, 10),
+		Scope:     ScopeRefresh,
+		ClientID:  rt.ClientID,
+		Exp:       rt.Expiry.Unix(),
+		Iat:       rt.CreatedAt.Unix(),
+		Role:      user.Role,
+		AppID:     user.AppId,
+		Username:  user.Email,
+		TokenType: tokenTypeRefresh,
+	}, nil
+}
+
+func (a *authService) introspectPersonalAccessToken(ctx context.Context, token string) (*domain.TokenIntrospection, error) {
+	const op = "service.introspect.introspectPersonalAccessToken"
+
+	pat, user, err := a.patRepo.GetPersonalAccessToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, domain.ErrTokenNotFound) {
+			return inactiveToken, nil
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	introspection := &domain.TokenIntrospection{
+		Active:    true,
+		Sub:       strconv.FormatInt(user.Id, 10),
+		Scope:     strings.Join(pat.Scopes, " "),
+		Iat:       pat.CreatedAt.Unix(),
+		Role:      user.Role,
+		AppID:     user.AppId,
+		Username:  user.Email,
+		TokenType: tokenTypeBearer,
+	}
+	// у бессрочного токена exp нет
+	if pat.ExpiresAt != nil {
+		introspection.Exp = pat.ExpiresAt.Unix()
+	}
+
+	return introspection, nil
+}
+
+// sub у токена сервиса - его client_id
+func (a *authService) introspectServiceToken(token string) (*domain.TokenIntrospection, error) {
+	const op = "service.introspect.introspectServiceToken"
+
+	info, err := a.tokenRepo.GetServiceTokenInfo(token)
+	if err != nil {
+		if errors.Is(err, domain.ErrTokenNotFound) {
+			return inactiveToken, nil
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &domain.TokenIntrospection{
+		Active:    true,
+		Sub:       info.Service.ClientID,
+		Scope:     info.Scope,
+		ClientID:  info.Service.ClientID,
+		Exp:       info.Expiry.Unix(),
+		Iat:       info.IssuedAt.Unix(),
+		TokenType: tokenTypeBearer,
+	}, nil
+}
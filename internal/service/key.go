@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/pkg/jwtauth"
+	"github.com/Iowel/app-auth-service/internal/repository/postgres"
+	"github.com/Iowel/app-auth-service/pkg/util"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// как часто реплики перечитывают ключи из базы
+	keyReloadInterval = time.Minute
+
+	// новый ключ активируем не сразу: сначала все реплики должны загрузить его для проверки подписей
+	keyActivationDelay = 2 * keyReloadInterval
+
+	// старым ключом проверяем подписи, пока не истекут выданные им токены (ID токены живут дольше access)
+	keyRetention = max(AccessTokenTTL, IDTokenTTL) + 5*time.Minute
+)
+
+var _ IKeyService = &keyService{}
+
+type IKeyService interface {
+	Init(ctx context.Context) error
+	Reload(ctx context.Context) error
+	Rotate(ctx context.Context) (*domain.SigningKey, error)
+	RunReloader(ctx context.Context, waitGroup *errgroup.Group)
+	JWKS() *jwtauth.JWKSet
+}
+
+type keyService struct {
+	keyRepo postgres.KeyRepository
+	signer  *jwtauth.Manager
+	secret  string
+}
+
+// signer == nil - JWT выключены, сервис ничего не делает
+func NewKeyService(keyRepo postgres.KeyRepository, signer *jwtauth.Manager, secret string) IKeyService {
+	return &keyService{
+		keyRepo: keyRepo,
+		signer:  signer,
+		secret:  secret,
+	}
+}
+
+// при первом запуске создаем ключ, затем загружаем все действующие ключи
+func (k *keyService) Init(ctx context.Context) error {
+	const op = "service.key.Init"
+
+	if k.signer == nil {
+		return nil
+	}
+
+	key, err := k.newSigningKey()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	created, err := k.keyRepo.CreateSigningKeyIfNone(ctx, key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if created {
+		log.Printf("created initial signing key, kid: %s", key.Kid)
+	}
+
+	return k.Reload(ctx)
+}
+
+func (k *keyService) Reload(ctx context.Context) error {
+	const op = "service.key.Reload"
+
+	if k.signer == nil {
+		return nil
+	}
+
+	kid, err := k.keyRepo.ActivateDueSigningKeyTx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if kid != "" {
+		log.Printf("signing key activated, kid: %s", kid)
+	}
+
+	stored, err := k.keyRepo.ListSigningKeys(ctx, keyRetention)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	keys := make([]*jwtauth.Key, 0, len(stored))
+	for _, s := range stored {
+		key := &jwtauth.Key{
+			Kid:       s.Kid,
+			PublicKey: ed25519.PublicKey(s.PublicKey),
+			Active:    s.IsActive,
+		}
+
+		// приватная часть нужна только активному ключу, ожидающий активации пока только проверяет подписи
+		if s.IsActive {
+			privateKey, err := util.Decrypt(k.secret, s.PrivateKey)
+			if err != nil {
+				return fmt.Errorf("%s: kid %s: %w", op, s.Kid, err)
+			}
+			key.PrivateKey = ed25519.PrivateKey(privateKey)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := k.signer.SetKeys(keys); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (k *keyService) Rotate(ctx context.Context) (*domain.SigningKey, error) {
+	const op = "service.key.Rotate"
+
+	if k.signer == nil {
+		return nil, domain.ErrJWTDisabled
+	}
+
+	key, err := k.newSigningKey()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = k.keyRepo.ScheduleSigningKeyTx(ctx, key, time.Now().Add(keyActivationDelay))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// публичная часть сразу попадает в JWKS; подписывать новым ключом начнет первая реплика,
+	// перечитавшая ключи после ActivatesAt, остальные - при своей следующей перезагрузке
+	if err := k.Reload(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Printf("signing key scheduled, new kid: %s, activates at: %s", key.Kid, key.ActivatesAt.Format(time.RFC3339))
+	return key, nil
+}
+
+func (k *keyService) RunReloader(ctx context.Context, waitGroup *errgroup.Group) {
+	const op = "service.key.RunReloader"
+
+	if k.signer == nil {
+		return
+	}
+
+	waitGroup.Go(func() error {
+		ticker := time.NewTicker(keyReloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("signing key reloader stopped")
+				return nil
+			case <-ticker.C:
+				if err := k.Reload(ctx); err != nil {
+					log.Printf("failed to reload signing keys, path: %s, error: %v\n", op, err)
+				}
+			}
+		}
+	})
+}
+
+func (k *keyService) JWKS() *jwtauth.JWKSet {
+	if k.signer == nil {
+		return &jwtauth.JWKSet{Keys: []jwtauth.JWK{}}
+	}
+	return k.signer.JWKS()
+}
+
+// генерим новый ключ, приватную часть шифруем секретом приложения
+func (k *keyService) newSigningKey() (*domain.SigningKey, error) {
+	key, err := jwtauth.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := util.Encrypt(k.secret, key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SigningKey{
+		Kid:        key.Kid,
+		Algorithm:  jwtauth.Algorithm,
+		PrivateKey: encrypted,
+		PublicKey:  key.PublicKey,
+	}, nil
+}
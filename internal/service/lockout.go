@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+)
+
+const (
+	defaultLoginMaxFailures      = 5
+	defaultLoginMaxFailuresPerIP = 50
+	defaultLoginLockoutDuration  = 15 * time.Minute
+
+	// задержка после n-й неудачи: 1s, 2s, 4s ... но не больше минуты
+	loginBaseDelay = time.Second
+	loginMaxDelay  = time.Minute
+)
+
+// когда блокировать вход; нули заменяются значениями по умолчанию
+type LockoutPolicy struct {
+	// неудачных попыток подряд на один аккаунт до блокировки
+	MaxFailures int64
+	// на один IP порог выше: за NAT'ом может сидеть целый офис
+	MaxFailuresPerIP int64
+	LockoutDuration  time.Duration
+}
+
+func (p LockoutPolicy) withDefaults() LockoutPolicy {
+	if p.MaxFailures <= 0 {
+		p.MaxFailures = defaultLoginMaxFailures
+	}
+	if p.MaxFailuresPerIP <= 0 {
+		p.MaxFailuresPerIP = defaultLoginMaxFailuresPerIP
+	}
+	if p.LockoutDuration <= 0 {
+		p.LockoutDuration = defaultLoginLockoutDuration
+	}
+	return p
+}
+
+// снимаем блокировку и обнуляем счетчики аккаунта
+func (a *authService) UnlockAccount(ctx context.Context, appID int64, email string) error {
+	const op = "service.lockout.UnlockAccount"
+
+	if _, err := a.userRepo.GetUserByEmail(appID, email); err != nil {
+		return err
+	}
+
+	account := accountSubject(appID, email)
+	err := a.attempts.Reset(ctx, loginFailuresKey(account), loginDelayKey(account), loginLockKey(account))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// проверяем до bcrypt: заблокированный аккаунт или IP не тратят CPU на сравнение паролей
+func (a *authService) checkLoginThrottle(ctx context.Context, appID int64, email, clientIP string) error {
+	const op = "service.lockout.checkLoginThrottle"
+
+	account, ip := accountSubject(appID, email), ipSubject(clientIP)
+
+	lockKeys := []string{loginLockKey(account)}
+	if ip != "" {
+		lockKeys = append(lockKeys, loginLockKey(ip))
+	}
+
+	// redis недоступен - пускаем, иначе его падение уронит все логины
+	locked, err := a.attempts.Blocked(ctx, lockKeys...)
+	if err != nil {
+		log.Printf("login throttle check failed: %s, error: %s", op, err)
+		return nil
+	}
+	if locked > 0 {
+		return &domain.LoginThrottledError{RetryAfter: locked, Locked: true}
+	}
+
+	delay, err := a.attempts.Blocked(ctx, loginDelayKey(account))
+	if err != nil {
+		log.Printf("login throttle check failed: %s, error: %s", op, err)
+		return nil
+	}
+	if delay > 0 {
+		return &domain.LoginThrottledError{RetryAfter: delay}
+	}
+
+	return nil
+}
+
+// неверный пароль или несуществующий email считаем одинаково, иначе по блокировкам можно перебирать email'ы
+func (a *authService) registerLoginFailure(ctx context.Context, appID int64, email, clientIP string) {
+	const op = "service.lockout.registerLoginFailure"
+
+	account, ip := accountSubject(appID, email), ipSubject(clientIP)
+
+	failures, err := a.attempts.Fail(ctx, loginFailuresKey(account), a.lockout.LockoutDuration)
+	if err != nil {
+		log.Printf("login failure tracking failed: %s, error: %s", op, err)
+		return
+	}
+
+	if failures >= a.lockout.MaxFailures {
+		a.lock(ctx, account)
+	} else if err := a.attempts.Block(ctx, loginDelayKey(account), loginDelay(failures)); err != nil {
+		log.Printf("login delay failed: %s, error: %s", op, err)
+	}
+
+	if ip == "" {
+		return
+	}
+
+	failures, err = a.attempts.Fail(ctx, loginFailuresKey(ip), a.lockout.LockoutDuration)
+	if err != nil {
+		log.Printf("login failure tracking failed: %s, error: %s", op, err)
+		return
+	}
+	if failures >= a.lockout.MaxFailuresPerIP {
+		a.lock(ctx, ip)
+	}
+}
+
+// успешный вход обнуляет счетчик аккаунта; счетчик IP живет до конца окна,
+// иначе один свой аккаунт позволял бы бесконечно перебирать чужие
+func (a *authService) resetLoginFailures(ctx context.Context, appID int64, email string) {
+	account := accountSubject(appID, email)
+	if err := a.attempts.Reset(ctx, loginFailuresKey(account), loginDelayKey(account)); err != nil {
+		log.Printf("login failures reset failed: %s", err)
+	}
+}
+
+func (a *authService) lock(ctx context.Context, subject string) {
+	log.Printf("login locked: %s for %s", subject, a.lockout.LockoutDuration)
+
+	if err := a.attempts.Block(ctx, loginLockKey(subject), a.lockout.LockoutDuration); err != nil {
+		log.Printf("login lock failed: %s, error: %s", subject, err)
+		return
+	}
+
+	// после блокировки начинаем считать заново
+	if err := a.attempts.Reset(ctx, loginFailuresKey(subject), loginDelayKey(subject)); err != nil {
+		log.Printf("login failures reset failed: %s, error: %s", subject, err)
+	}
+}
+
+func loginDelay(failures int64) time.Duration {
+	delay := loginBaseDelay
+	for i := int64(1); i < failures && delay < loginMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, loginMaxDelay)
+}
+
+// один email в разных приложениях - разные аккаунты со своими счетчиками
+func accountSubject(appID int64, email string) string {
+	return "account:" + strconv.FormatInt(appID, 10) + ":" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// из peer приходит адрес вместе с портом
+func ipSubject(clientIP string) string {
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		clientIP = host
+	}
+	if clientIP == "" {
+		return ""
+	}
+	return "ip:" + clientIP
+}
+
+func loginFailuresKey(subject string) string {
+	return "login:failures:" + subject
+}
+
+func loginDelayKey(subject string) string {
+	return "login:delay:" + subject
+}
+
+func loginLockKey(subject string) string {
+	return "login:lock:" + subject
+}
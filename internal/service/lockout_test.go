@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+// задержка удваивается с каждой неудачей и упирается в loginMaxDelay
+func TestLoginDelay(t *testing.T) {
+	tests := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 32 * time.Second},
+		{7, time.Minute},
+		{100, time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := loginDelay(tt.failures); got != tt.want {
+			t.Errorf("loginDelay(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestAccountSubject(t *testing.T) {
+	// регистр и пробелы email не дают обойти счетчик
+	if a, b := accountSubject(1, "User@Example.com "), accountSubject(1, "user@example.com"); a != b {
+		t.Errorf("accountSubject differs for the same email: %q != %q", a, b)
+	}
+	// в разных приложениях - разные аккаунты
+	if a, b := accountSubject(1, "user@example.com"), accountSubject(2, "user@example.com"); a == b {
+		t.Errorf("accountSubject is shared between apps: %q", a)
+	}
+}
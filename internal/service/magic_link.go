@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+)
+
+// как и со сбросом пароля, ответ не зависит от того, есть ли такой email
+func (a *authService) RequestMagicLink(ctx context.Context, params domain.RequestMagicLinkParams) error {
+	const op = "service.magic_link.RequestMagicLink"
+
+	user, err := a.userRepo.GetUserByEmail(domain.AppIDOrDefault(params.AppID), params.Email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = params.AfterFind(user)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ссылка из письма заменяет пароль, но не второй фактор
+func (a *authService) ConsumeMagicLink(ctx context.Context, params domain.ConsumeMagicLinkParams) (*domain.LoginResult, error) {
+	const op = "service.magic_link.ConsumeMagicLink"
+
+	hash := sha256.Sum256([]byte(params.Code))
+	user, err := a.mailRepo.ConsumeMagicLinkTx(ctx, domain.ConsumeMagicLinkTxParams{
+		LinkID:    params.LinkID,
+		CodeHash:  hash[:],
+		UserAgent: params.UserAgent,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMagicLink) {
+			return nil, domain.ErrInvalidMagicLink
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	twoFactor, err := a.twoFactorEnabled(ctx, user.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if twoFactor {
+		challenge, err := a.createTwoFactorChallenge(ctx, user, params.UserAgent, params.ClientIP)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return &domain.LoginResult{ChallengeToken: challenge}, nil
+	}
+
+	tokens, err := a.IssueTokens(ctx, user, domain.IssueTokensParams{
+		Scope:     ScopeAuthentication,
+		UserAgent: params.UserAgent,
+		ClientIP:  params.ClientIP,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &domain.LoginResult{Tokens: tokens}, nil
+}
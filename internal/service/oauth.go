@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/pkg/jwtauth"
+	"github.com/Iowel/app-auth-service/internal/repository/postgres"
+	"github.com/Iowel/app-auth-service/pkg/pb"
+)
+
+const (
+	ResponseTypeCode = "code"
+
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+
+	// plain не поддерживаем - он ничего не защищает, если код перехватили вместе с запросом
+	CodeChallengeMethodS256 = "S256"
+
+	authorizationCodeTTL = 10 * time.Minute
+)
+
+var _ IOAuthService = &oauthService{}
+
+type IOAuthService interface {
+	ValidateAuthorizeRequest(ctx context.Context, req *domain.AuthorizeRequest) (*domain.OAuthClient, error)
+	NeedsConsent(ctx context.Context, user *pb.User, client *domain.OAuthClient, scope string) (bool, error)
+	Authorize(ctx context.Context, user *pb.User, client *domain.OAuthClient, req *domain.AuthorizeRequest) (string, error)
+
+	AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*domain.OAuthClient, error)
+	ExchangeCode(ctx context.Context, client *domain.OAuthClient, params domain.CodeExchangeParams) (*domain.OAuthTokenResponse, error)
+	RefreshToken(ctx context.Context, client *domain.OAuthClient, refreshToken string) (*domain.OAuthTokenResponse, error)
+	ClientCredentials(ctx context.Context, client *domain.OAuthClient, scope string) (*domain.OAuthTokenResponse, error)
+
+	// машинные клиенты
+	RegisterServiceClient(ctx context.Context, params domain.RegisterServiceClientParams) (*domain.ServiceClientCredentials, error)
+	RotateServiceClientSecret(ctx context.Context, clientID string) (*domain.ServiceClientCredentials, error)
+
+	// OpenID Connect
+	Issuer() string
+	UserInfo(ctx context.Context, accessToken string) (*domain.UserInfo, error)
+}
+
+type oauthService struct {
+	oauthRepo   postgres.OAuthRepository
+	userRepo    postgres.UserRepository
+	tokenRepo   *postgres.TokenRepository
+	authService IAuthService
+
+	// подписывает ID токены; nil - OpenID Connect выключен
+	signer *jwtauth.Manager
+}
+
+func NewOAuthService(oauthRepo postgres.OAuthRepository, userRepo postgres.UserRepository, tokenRepo *postgres.TokenRepository, authService IAuthService, signer *jwtauth.Manager) IOAuthService {
+	return &oauthService{
+		oauthRepo:   oauthRepo,
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		authService: authService,
+		signer:      signer,
+	}
+}
+
+// проверяем клиента и параметры запроса; ошибки клиента и redirect_uri нельзя отдавать редиректом
+func (o *oauthService) ValidateAuthorizeRequest(ctx context.Context, req *domain.AuthorizeRequest) (*domain.OAuthClient, error) {
+	const op = "service.oauth.ValidateAuthorizeRequest"
+
+	client, err := o.oauthRepo.GetClient(ctx, req.ClientID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidClient) {
+			return nil, domain.ErrInvalidClient
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// redirect_uri можно не передавать, только если у клиента он один
+	if req.RedirectURI == "" && len(client.RedirectURIs) == 1 {
+		req.RedirectURI = client.RedirectURIs[0]
+	}
+	if !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		return nil, domain.ErrInvalidRedirectURI
+	}
+
+	if req.ResponseType != ResponseTypeCode {
+		return nil, domain.ErrUnsupportedResponseType
+	}
+
+	if req.CodeChallenge == "" {
+		return nil, fmt.Errorf("%w: code_challenge is required", domain.ErrInvalidOAuthRequest)
+	}
+	if req.CodeChallengeMethod != CodeChallengeMethodS256 {
+		return nil, fmt.Errorf("%w: code_challenge_method must be S256", domain.ErrInvalidOAuthRequest)
+	}
+
+	scopes := strings.Fields(req.Scope)
+	if len(scopes) == 0 {
+		scopes = client.AllowedScopes
+	}
+	for _, scope := range scopes {
+		if scope == ScopeAuthentication || scope == ScopeRefresh || !slices.Contains(client.AllowedScopes, scope) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrInvalidScope, scope)
+		}
+		if scope == ScopeOpenID && o.Issuer() == "" {
+			return nil, fmt.Errorf("%w: openid connect is disabled", domain.ErrInvalidScope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, domain.ErrInvalidScope
+	}
+	req.Scope = strings.Join(scopes, " ")
+
+	return client, nil
+}
+
+// экран согласия показываем только сторонним приложениям и только при новых scopes
+func (o *oauthService) NeedsConsent(ctx context.Context, user *pb.User, client *domain.OAuthClient, scope string) (bool, error) {
+	const op = "service.oauth.NeedsConsent"
+
+	if client.IsFirstParty {
+		return false, nil
+	}
+
+	granted, err := o.oauthRepo.GetConsent(ctx, user.Id, client.ClientID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, s := range strings.Fields(scope) {
+		if !slices.Contains(granted, s) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// запоминаем согласие и выдаем одноразовый код авторизации
+func (o *oauthService) Authorize(ctx context.Context, user *pb.User, client *domain.OAuthClient, req *domain.AuthorizeRequest) (string, error) {
+	const op = "service.oauth.Authorize"
+
+	if !client.IsFirstParty {
+		granted, err := o.oauthRepo.GetConsent(ctx, user.Id, client.ClientID)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, s := range strings.Fields(req.Scope) {
+			if !slices.Contains(granted, s) {
+				granted = append(granted, s)
+			}
+		}
+
+		err = o.oauthRepo.SaveConsent(ctx, user.Id, client.ClientID, granted)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// код генерим так же, как токен: клиенту plaintext, в базу хеш
+	token, err := GenerateToken(user.Id, authorizationCodeTTL, "")
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = o.oauthRepo.CreateAuthorizationCode(ctx, &domain.AuthorizationCode{
+		Hash:                token.Hash,
+		ClientID:            client.ClientID,
+		UserID:              user.Id,
+		RedirectURI:         req.RedirectURI,
+		Scope:               req.Scope,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		Nonce:               req.Nonce,
+		ExpiredAt:           token.Expiry.AsTime(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token.Plaintext, nil
+}
+
+// конфиденциальный клиент обязан предъявить секрет, публичный - только client_id
+func (o *oauthService) AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*domain.OAuthClient, error) {
+	const op = "service.oauth.AuthenticateClient"
+
+	if clientID == "" {
+		return nil, domain.ErrInvalidClient
+	}
+
+	client, err := o.oauthRepo.GetClient(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidClient) {
+			return nil, domain.ErrInvalidClient
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if client.IsPublic() {
+		if clientSecret != "" {
+			return nil, domain.ErrInvalidClient
+		}
+		return client, nil
+	}
+
+	hash := sha256.Sum256([]byte(clientSecret))
+	if clientSecret == "" || subtle.ConstantTimeCompare(hash[:], client.SecretHash) != 1 {
+		return nil, domain.ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+func (o *oauthService) ExchangeCode(ctx context.Context, client *domain.OAuthClient, params domain.CodeExchangeParams) (*domain.OAuthTokenResponse, error) {
+	const op = "service.oauth.ExchangeCode"
+
+	if params.Code == "" || params.CodeVerifier == "" {
+		return nil, fmt.Errorf("%w: code and code_verifier are required", domain.ErrInvalidOAuthRequest)
+	}
+
+	codeHash := sha256.Sum256([]byte(params.Code))
+	code, err := o.oauthRepo.ConsumeAuthorizationCode(ctx, codeHash[:])
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidGrant) {
+			return nil, domain.ErrInvalidGrant
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if code.ClientID != client.ClientID || code.RedirectURI != params.RedirectURI {
+		return nil, domain.ErrInvalidGrant
+	}
+
+	if !verifyCodeChallenge(code.CodeChallenge, params.CodeVerifier) {
+		return nil, domain.ErrInvalidGrant
+	}
+
+	user, err := o.userRepo.GetUserByID(ctx, code.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := o.authService.IssueTokens(ctx, user, domain.IssueTokensParams{
+		Scope:     code.Scope,
+		ClientID:  client.ClientID,
+		UserAgent: params.UserAgent,
+		ClientIP:  params.ClientIP,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp := oauthTokenResponse(tokens)
+
+	if slices.Contains(strings.Fields(code.Scope), ScopeOpenID) {
+		resp.IDToken, err = o.idToken(user, client, code, tokens)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return resp, nil
+}
+
+func (o *oauthService) RefreshToken(ctx context.Context, client *domain.OAuthClient, refreshToken string) (*domain.OAuthTokenResponse, error) {
+	const op = "service.oauth.RefreshToken"
+
+	tokens, err := o.authService.RefreshToken(ctx, refreshToken, client.ClientID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) || errors.Is(err, domain.ErrRefreshTokenReused) {
+			return nil, domain.ErrInvalidGrant
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return oauthTokenResponse(tokens), nil
+}
+
+// client_credentials (RFC 6749, 4.4): токен выдается самому сервису, refresh токена нет
+func (o *oauthService) ClientCredentials(ctx context.Context, client *domain.OAuthClient, scope string) (*domain.OAuthTokenResponse, error) {
+	const op = "service.oauth.ClientCredentials"
+
+	if !client.IsService || client.IsPublic() {
+		return nil, domain.ErrUnauthorizedClient
+	}
+
+	scopes := strings.Fields(scope)
+	if len(scopes) == 0 {
+		scopes = client.AllowedScopes
+	}
+	for _, s := range scopes {
+		if s == ScopeAuthentication || s == ScopeRefresh || !slices.Contains(client.AllowedScopes, s) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrInvalidScope, s)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, domain.ErrInvalidScope
+	}
+
+	token, err := GenerateServiceToken(ServiceTokenTTL, strings.Join(scopes, " "))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = o.tokenRepo.InsertServiceToken(token, client.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &domain.OAuthTokenResponse{
+		AccessToken: token.Plaintext,
+		TokenType:   tokenTypeBearer,
+		ExpiresIn:   int64(ServiceTokenTTL.Seconds()),
+		Scope:       token.Scope,
+	}, nil
+}
+
+// PKCE (RFC 7636): BASE64URL(SHA256(code_verifier)) должен совпасть с code_challenge
+func verifyCodeChallenge(challenge, verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+func oauthTokenResponse(tokens *domain.AuthTokens) *domain.OAuthTokenResponse {
+	return &domain.OAuthTokenResponse{
+		AccessToken:  tokens.AccessToken.Plaintext,
+		TokenType:    tokenTypeBearer,
+		ExpiresIn:    int64(time.Until(tokens.AccessToken.Expiry.AsTime()).Seconds()),
+		RefreshToken: tokens.RefreshToken.Plaintext,
+		Scope:        tokens.AccessToken.Scope,
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+// RFC 7636, приложение B
+func TestVerifyCodeChallenge(t *testing.T) {
+	const (
+		verifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+		challenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	)
+
+	tests := []struct {
+		name      string
+		challenge string
+		verifier  string
+		want      bool
+	}{
+		{"rfc example", challenge, verifier, true},
+		{"wrong verifier", challenge, verifier[:42] + "Y", false},
+		{"plain method is not supported", verifier, verifier, false},
+		{"padded challenge", challenge + "=", verifier, false},
+		{"verifier too short", challenge, verifier[:42], false},
+		{"verifier too long", challenge, strings.Repeat("a", 129), false},
+		{"empty", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyCodeChallenge(tt.challenge, tt.verifier); got != tt.want {
+				t.Errorf("verifyCodeChallenge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/pkg/jwtauth"
+	"github.com/Iowel/app-auth-service/pkg/pb"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// OpenID Connect поверх OAuth 2.0: ID токены и /userinfo
+
+// пустой issuer - OpenID Connect выключен
+func (o *oauthService) Issuer() string {
+	if o.signer == nil {
+		return ""
+	}
+	return o.signer.Issuer()
+}
+
+// ID токен для клиента, запросившего scope openid
+func (o *oauthService) idToken(user *pb.User, client *domain.OAuthClient, code *domain.AuthorizationCode, tokens *domain.AuthTokens) (string, error) {
+	info := userInfo(user, code.Scope)
+
+	claims := &jwtauth.IDTokenClaims{
+		Nonce:            code.Nonce,
+		AccessTokenHash:  jwtauth.AccessTokenHash(tokens.AccessToken.Plaintext),
+		Email:            info.Email,
+		EmailVerified:    info.EmailVerified,
+		Name:             info.Name,
+		RegisteredClaims: jwtauth.NewClaims(info.Sub, IDTokenTTL),
+	}
+	claims.Audience = jwt.ClaimStrings{client.ClientID}
+
+	return o.signer.SignIDToken(claims)
+}
+
+// claims о юзвере по токену, выданному со scope openid
+func (o *oauthService) UserInfo(ctx context.Context, accessToken string) (*domain.UserInfo, error) {
+	const op = "service.oidc.UserInfo"
+
+	if accessToken == "" {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	if o.signer != nil && jwtauth.LooksLikeJWT(accessToken) {
+		if _, err := o.signer.Verify(accessToken); err != nil {
+			return nil, fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+		}
+	}
+
+	info, err := o.tokenRepo.GetTokenInfo(accessToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrTokenNotFound) {
+			return nil, domain.ErrUnauthenticated
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	scope := info.Scope
+
+	// наши собственные токены дают полный доступ к аккаунту
+	if scope == ScopeAuthentication {
+		scope = strings.Join([]string{ScopeOpenID, ScopeProfile, ScopeEmail}, " ")
+	}
+	if !slices.Contains(strings.Fields(scope), ScopeOpenID) {
+		return nil, domain.ErrInsufficientScope
+	}
+
+	return userInfo(info.User, scope), nil
+}
+
+// отдаем только то, на что юзверь дал согласие
+func userInfo(user *pb.User, scope string) *domain.UserInfo {
+	scopes := strings.Fields(scope)
+
+	info := &domain.UserInfo{
+		Sub: strconv.FormatInt(user.Id, 10),
+	}
+
+	if slices.Contains(scopes, ScopeEmail) {
+		verified := user.Isemailverified
+		info.Email = user.Email
+		info.EmailVerified = &verified
+	}
+
+	if slices.Contains(scopes, ScopeProfile) {
+		info.Name = user.Name
+	}
+
+	return info
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/repository/postgres"
+	"github.com/Iowel/app-auth-service/pkg/pb"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const (
+	// сколько живет challenge между Begin* и Finish*
+	PasskeyCeremonyTTL = 5 * time.Minute
+
+	defaultPasskeyName   = "Passkey"
+	maxPasskeyNameLength = 64
+	defaultPasskeyRPName = "app-auth-service"
+)
+
+var _ IPasskeyService = &passkeyService{}
+
+type IPasskeyService interface {
+	BeginPasskeyRegistration(ctx context.Context) (*domain.PasskeyOptions, error)
+	FinishPasskeyRegistration(ctx context.Context, params domain.FinishPasskeyRegistrationParams) (*domain.Passkey, error)
+	BeginPasskeyLogin(ctx context.Context) (*domain.PasskeyOptions, error)
+	FinishPasskeyLogin(ctx context.Context, params domain.FinishPasskeyLoginParams) (*domain.AuthTokens, error)
+}
+
+type passkeyService struct {
+	passkeyRepo postgres.PasskeyRepository
+	userRepo    postgres.UserRepository
+	authService IAuthService
+
+	// nil - passkeys выключены (не задан WEBAUTHN_RP_ID)
+	webAuthn *webauthn.WebAuthn
+}
+
+func NewPasskeyService(passkeyRepo postgres.PasskeyRepository, userRepo postgres.UserRepository, authService IAuthService, webAuthn *webauthn.WebAuthn) IPasskeyService {
+	return &passkeyService{
+		passkeyRepo: passkeyRepo,
+		userRepo:    userRepo,
+		authService: authService,
+		webAuthn:    webAuthn,
+	}
+}
+
+// relying party: домен, к которому браузер привяжет passkey, и origins фронтенда, с которых идут церемонии.
+// Требуем discoverable credential и проверку юзверя (биометрия/PIN) - тогда passkey заменяет и пароль, и второй фактор
+func NewWebAuthn(rpID, rpName string, origins []string) (*webauthn.WebAuthn, error) {
+	if rpName == "" {
+		rpName = defaultPasskeyRPName
+	}
+
+	return webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: rpName,
+		RPOrigins:     origins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		},
+	})
+}
+
+// начало регистрации passkey для залогиненного юзверя
+func (p *passkeyService) BeginPasskeyRegistration(ctx context.Context) (*domain.PasskeyOptions, error) {
+	const op = "service.passkey.BeginPasskeyRegistration"
+
+	if p.webAuthn == nil {
+		return nil, domain.ErrPasskeysDisabled
+	}
+
+	user, err := p.authService.AuthorizeUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+
+	passkeys, err := p.passkeyRepo.ListPasskeys(ctx, user.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	webAuthnUser := &passkeyUser{user: user, passkeys: passkeys}
+
+	// не даем второй раз зарегистрировать тот же аутентификатор
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(passkeys))
+	for _, credential := range webAuthnUser.WebAuthnCredentials() {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	creation, session, err := p.webAuthn.BeginRegistration(webAuthnUser, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	options, err := p.createCeremony(ctx, &user.Id, domain.PasskeyCeremonyRegistration, creation, session)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return options, nil
+}
+
+// ответ navigator.credentials.create: проверяем attestation и сохраняем публичный ключ
+func (p *passkeyService) FinishPasskeyRegistration(ctx context.Context, params domain.FinishPasskeyRegistrationParams) (*domain.Passkey, error) {
+	const op = "service.passkey.FinishPasskeyRegistration"
+
+	if p.webAuthn == nil {
+		return nil, domain.ErrPasskeysDisabled
+	}
+
+	user, err := p.authService.AuthorizeUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+
+	ceremony, session, err := p.consumeCeremony(ctx, params.SessionToken, domain.PasskeyCeremonyRegistration)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPasskeyCeremony) {
+			return nil, domain.ErrInvalidPasskeyCeremony
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// церемонию начинал этот же юзверь
+	if ceremony.UserID == nil || *ceremony.UserID != user.Id {
+		return nil, domain.ErrInvalidPasskeyCeremony
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(params.Credential)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidPasskeyCredential, passkeyErrorDetails(err))
+	}
+
+	credential, err := p.webAuthn.CreateCredential(&passkeyUser{user: user}, *session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidPasskeyCredential, passkeyErrorDetails(err))
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	passkey := &domain.Passkey{
+		UserID:          user.Id,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		Flags:           uint8(credential.Flags.ProtocolValue()),
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		Name:            passkeyName(params.Name),
+	}
+
+	err = p.passkeyRepo.CreatePasskey(ctx, passkey)
+	if err != nil {
+		if errors.Is(err, domain.ErrPasskeyExists) {
+			return nil, domain.ErrPasskeyExists
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return passkey, nil
+}
+
+// начало входа: юзверя не спрашиваем, браузер сам предложит passkeys для нашего RP ID
+func (p *passkeyService) BeginPasskeyLogin(ctx context.Context) (*domain.PasskeyOptions, error) {
+	const op = "service.passkey.BeginPasskeyLogin"
+
+	if p.webAuthn == nil {
+		return nil, domain.ErrPasskeysDisabled
+	}
+
+	assertion, session, err := p.webAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	options, err := p.createCeremony(ctx, nil, domain.PasskeyCeremonyLogin, assertion, session)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return options, nil
+}
+
+// ответ navigator.credentials.get: проверяем подпись и выдаем те же токены, что и Login
+func (p *passkeyService) FinishPasskeyLogin(ctx context.Context, params domain.FinishPasskeyLoginParams) (*domain.AuthTokens, error) {
+	const op = "service.passkey.FinishPasskeyLogin"
+
+	if p.webAuthn == nil {
+		return nil, domain.ErrPasskeysDisabled
+	}
+
+	_, session, err := p.consumeCeremony(ctx, params.SessionToken, domain.PasskeyCeremonyLogin)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPasskeyCeremony) {
+			return nil, domain.ErrInvalidPasskeyCeremony
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(params.Credential)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidPasskeyCredential, passkeyErrorDetails(err))
+	}
+
+	// user handle - это id юзверя, который мы отдали при регистрации
+	var webAuthnUser *passkeyUser
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		userID, err := strconv.ParseInt(string(userHandle), 10, 64)
+		if err != nil {
+			return nil, domain.ErrUserNotFound
+		}
+
+		user, err := p.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		passkeys, err := p.passkeyRepo.ListPasskeys(ctx, user.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		webAuthnUser = &passkeyUser{user: user, passkeys: passkeys}
+		return webAuthnUser, nil
+	}
+
+	_, credential, err := p.webAuthn.ValidatePasskeyLogin(handler, *session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidPasskeyCredential, passkeyErrorDetails(err))
+	}
+
+	// счетчик подписей не вырос - похоже на копию ключа
+	if credential.Authenticator.CloneWarning {
+		log.Printf("Passkey clone warning: path: %s, user: %d", op, webAuthnUser.user.Id)
+		return nil, fmt.Errorf("%w: sign counter did not increase", domain.ErrInvalidPasskeyCredential)
+	}
+
+	passkey := webAuthnUser.passkey(credential.ID)
+	if passkey == nil {
+		return nil, domain.ErrInvalidPasskeyCredential
+	}
+
+	// до сохранения счетчика: отклоненный вход не должен менять состояние ключа
+	if !webAuthnUser.user.Isemailverified {
+		return nil, domain.Isemailverified
+	}
+
+	err = p.passkeyRepo.UpdatePasskeyUsage(ctx, passkey.ID, credential.Authenticator.SignCount, uint8(parsed.Response.AuthenticatorData.Flags))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := p.authService.IssueTokens(ctx, webAuthnUser.user, domain.IssueTokensParams{
+		Scope:     ScopeAuthentication,
+		UserAgent: params.UserAgent,
+		ClientIP:  params.ClientIP,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+// сохраняем SessionData у себя, браузеру отдаем только одноразовый токен церемонии
+func (p *passkeyService) createCeremony(ctx context.Context, userID *int64, kind string, options any, session *webauthn.SessionData) (*domain.PasskeyOptions, error) {
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		return nil, err
+	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := GenerateToken(0, PasskeyCeremonyTTL, "")
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.passkeyRepo.CreateCeremony(ctx, &domain.PasskeyCeremony{
+		Hash:        token.Hash,
+		UserID:      userID,
+		Kind:        kind,
+		SessionData: sessionData,
+		ExpiredAt:   token.Expiry.AsTime(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PasskeyOptions{
+		SessionToken: token.Plaintext,
+		Options:      optionsJSON,
+	}, nil
+}
+
+// токен церемонии сгорает сразу, даже если ответ аутентификатора не пройдет проверку
+func (p *passkeyService) consumeCeremony(ctx context.Context, sessionToken, kind string) (*domain.PasskeyCeremony, *webauthn.SessionData, error) {
+	hash := sha256.Sum256([]byte(sessionToken))
+	ceremony, err := p.passkeyRepo.ConsumeCeremony(ctx, hash[:], kind)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(ceremony.SessionData, &session); err != nil {
+		return nil, nil, err
+	}
+
+	return ceremony, &session, nil
+}
+
+// юзверь в терминах go-webauthn
+type passkeyUser struct {
+	user     *pb.User
+	passkeys []*domain.Passkey
+}
+
+func (u *passkeyUser) WebAuthnID() []byte {
+	return []byte(strconv.FormatInt(u.user.Id, 10))
+}
+
+func (u *passkeyUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *passkeyUser) WebAuthnDisplayName() string {
+	return u.user.Name
+}
+
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, p := range u.passkeys {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(p.Transports))
+		for _, transport := range p.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+
+		credentials = append(credentials, webauthn.Credential{
+			ID:              p.CredentialID,
+			PublicKey:       p.PublicKey,
+			AttestationType: p.AttestationType,
+			Transport:       transports,
+			Flags:           webauthn.NewCredentialFlags(protocol.AuthenticatorFlags(p.Flags)),
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    p.AAGUID,
+				SignCount: p.SignCount,
+			},
+		})
+	}
+	return credentials
+}
+
+func (u *passkeyUser) passkey(credentialID []byte) *domain.Passkey {
+	for _, p := range u.passkeys {
+		if bytes.Equal(p.CredentialID, credentialID) {
+			return p
+		}
+	}
+	return nil
+}
+
+// protocol.Error прячет причину в DevInfo, в логах она нужна
+func passkeyErrorDetails(err error) string {
+	var protoErr *protocol.Error
+	if errors.As(err, &protoErr) && protoErr.DevInfo != "" {
+		return protoErr.Details + ": " + protoErr.DevInfo
+	}
+	return err.Error()
+}
+
+func passkeyName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return defaultPasskeyName
+	}
+
+	if runes := []rune(name); len(runes) > maxPasskeyNameLength {
+		name = string(runes[:maxPasskeyNameLength])
+	}
+	return name
+}
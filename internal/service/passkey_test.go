@@ -0,0 +1,418 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/repository/postgres"
+	"github.com/Iowel/app-auth-service/pkg/pb"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+// полный круг: регистрация passkey залогиненным юзверем и вход им же
+func TestPasskeyRegisterAndLogin(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+
+	passkey := env.register(t, "Ноутбук")
+	if passkey.Name != "Ноутбук" || passkey.UserID != env.user.Id {
+		t.Fatalf("unexpected passkey: %+v", passkey)
+	}
+
+	tokens, err := env.login(t)
+	if err != nil {
+		t.Fatalf("FinishPasskeyLogin: %v", err)
+	}
+	if tokens.AccessToken.GetUserid() != env.user.Id {
+		t.Fatalf("tokens issued for user %d, want %d", tokens.AccessToken.GetUserid(), env.user.Id)
+	}
+	if got := env.repo.passkeys[0].SignCount; got != 1 {
+		t.Fatalf("sign count = %d, want 1", got)
+	}
+
+	// второй вход с растущим счетчиком тоже проходит
+	if _, err := env.login(t); err != nil {
+		t.Fatalf("second FinishPasskeyLogin: %v", err)
+	}
+}
+
+// токен церемонии одноразовый
+func TestPasskeyCeremonyReplay(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	ctx := context.Background()
+
+	env.register(t, "")
+
+	options, err := env.service.BeginPasskeyLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginPasskeyLogin: %v", err)
+	}
+	credential := env.authenticator.get(t, options.Options)
+
+	params := domain.FinishPasskeyLoginParams{SessionToken: options.SessionToken, Credential: credential}
+	if _, err := env.service.FinishPasskeyLogin(ctx, params); err != nil {
+		t.Fatalf("FinishPasskeyLogin: %v", err)
+	}
+
+	_, err = env.service.FinishPasskeyLogin(ctx, params)
+	if !errors.Is(err, domain.ErrInvalidPasskeyCeremony) {
+		t.Fatalf("replayed ceremony: got %v, want %v", err, domain.ErrInvalidPasskeyCeremony)
+	}
+}
+
+// счетчик подписей не вырос - копия ключа, не пускаем
+func TestPasskeyCloneWarning(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+
+	env.register(t, "")
+	if _, err := env.login(t); err != nil {
+		t.Fatalf("FinishPasskeyLogin: %v", err)
+	}
+
+	env.authenticator.signCount = 0
+
+	_, err := env.login(t)
+	if !errors.Is(err, domain.ErrInvalidPasskeyCredential) {
+		t.Fatalf("cloned authenticator: got %v, want %v", err, domain.ErrInvalidPasskeyCredential)
+	}
+}
+
+// ответ на challenge, выданный для другого origin, не принимаем
+func TestPasskeyWrongOrigin(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+
+	env.register(t, "")
+	env.authenticator.origin = "https://evil.example"
+
+	_, err := env.login(t)
+	if !errors.Is(err, domain.ErrInvalidPasskeyCredential) {
+		t.Fatalf("wrong origin: got %v, want %v", err, domain.ErrInvalidPasskeyCredential)
+	}
+}
+
+func TestPasskeyUnverifiedEmail(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+
+	passkey := env.register(t, "")
+	signCount := passkey.SignCount
+	env.user.Isemailverified = false
+
+	_, err := env.login(t)
+	if !errors.Is(err, domain.Isemailverified) {
+		t.Fatalf("unverified email: got %v, want %v", err, domain.Isemailverified)
+	}
+
+	// отклоненный вход не трогает счетчик ключа
+	if passkey.SignCount != signCount {
+		t.Errorf("sign count changed on rejected login: got %d, want %d", passkey.SignCount, signCount)
+	}
+}
+
+type passkeyTestEnv struct {
+	user          *pb.User
+	repo          *fakePasskeyRepo
+	service       IPasskeyService
+	authenticator *softAuthenticator
+}
+
+func newPasskeyTestEnv(t *testing.T) *passkeyTestEnv {
+	t.Helper()
+
+	webAuthn, err := NewWebAuthn(testRPID, "", []string{testOrigin})
+	if err != nil {
+		t.Fatalf("NewWebAuthn: %v", err)
+	}
+
+	user := &pb.User{Id: 42, Email: "user@example.com", Name: "user", Isemailverified: true}
+	repo := &fakePasskeyRepo{ceremonies: map[string]*domain.PasskeyCeremony{}}
+
+	return &passkeyTestEnv{
+		user:          user,
+		repo:          repo,
+		service:       NewPasskeyService(repo, &fakeUserRepo{user: user}, &fakeAuthService{user: user}, webAuthn),
+		authenticator: newSoftAuthenticator(t, []byte(strconv.FormatInt(user.Id, 10))),
+	}
+}
+
+func (e *passkeyTestEnv) register(t *testing.T, name string) *domain.Passkey {
+	t.Helper()
+	ctx := context.Background()
+
+	options, err := e.service.BeginPasskeyRegistration(ctx)
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration: %v", err)
+	}
+
+	passkey, err := e.service.FinishPasskeyRegistration(ctx, domain.FinishPasskeyRegistrationParams{
+		SessionToken: options.SessionToken,
+		Credential:   e.authenticator.create(t, options.Options),
+		Name:         name,
+	})
+	if err != nil {
+		t.Fatalf("FinishPasskeyRegistration: %v", err)
+	}
+
+	return passkey
+}
+
+func (e *passkeyTestEnv) login(t *testing.T) (*domain.AuthTokens, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	options, err := e.service.BeginPasskeyLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginPasskeyLogin: %v", err)
+	}
+
+	return e.service.FinishPasskeyLogin(ctx, domain.FinishPasskeyLoginParams{
+		SessionToken: options.SessionToken,
+		Credential:   e.authenticator.get(t, options.Options),
+	})
+}
+
+// программный аутентификатор: ES256, attestation "none", биометрия "пройдена" всегда
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+	origin       string
+}
+
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttestedData = 0x40
+)
+
+func newSoftAuthenticator(t *testing.T, userHandle []byte) *softAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		t.Fatalf("rand: %v", err)
+	}
+
+	return &softAuthenticator{
+		key:          key,
+		credentialID: credentialID,
+		userHandle:   userHandle,
+		origin:       testOrigin,
+	}
+}
+
+// navigator.credentials.create
+func (a *softAuthenticator) create(t *testing.T, options []byte) []byte {
+	t.Helper()
+
+	clientData := a.clientData(t, "webauthn.create", options)
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.key.PublicKey.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatalf("marshal cose key: %v", err)
+	}
+
+	authData := a.authData(flagUserPresent | flagUserVerified | flagAttestedData)
+	authData = append(authData, make([]byte, 16)...) // aaguid
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatalf("marshal attestation: %v", err)
+	}
+
+	return a.credential(t, map[string]string{
+		"clientDataJSON":    b64(clientData),
+		"attestationObject": b64(attestation),
+	})
+}
+
+// navigator.credentials.get
+func (a *softAuthenticator) get(t *testing.T, options []byte) []byte {
+	t.Helper()
+
+	clientData := a.clientData(t, "webauthn.get", options)
+
+	a.signCount++
+	authData := a.authData(flagUserPresent | flagUserVerified)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(bytes.Clone(authData), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	return a.credential(t, map[string]string{
+		"clientDataJSON":    b64(clientData),
+		"authenticatorData": b64(authData),
+		"signature":         b64(signature),
+		"userHandle":        b64(a.userHandle),
+	})
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, kind string, options []byte) []byte {
+	t.Helper()
+
+	var parsed struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+		} `json:"publicKey"`
+	}
+	if err := json.Unmarshal(options, &parsed); err != nil {
+		t.Fatalf("unmarshal options: %v", err)
+	}
+
+	clientData, err := json.Marshal(map[string]string{
+		"type":      kind,
+		"challenge": parsed.PublicKey.Challenge,
+		"origin":    a.origin,
+	})
+	if err != nil {
+		t.Fatalf("marshal client data: %v", err)
+	}
+	return clientData
+}
+
+func (a *softAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	return binary.BigEndian.AppendUint32(data, a.signCount)
+}
+
+func (a *softAuthenticator) credential(t *testing.T, response map[string]string) []byte {
+	t.Helper()
+
+	credential, err := json.Marshal(map[string]any{
+		"id":       b64(a.credentialID),
+		"rawId":    b64(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatalf("marshal credential: %v", err)
+	}
+	return credential
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// репозитории и auth сервис в памяти; не реализованные методы паникуют через nil интерфейс
+
+type fakePasskeyRepo struct {
+	passkeys   []*domain.Passkey
+	ceremonies map[string]*domain.PasskeyCeremony
+}
+
+func (r *fakePasskeyRepo) CreatePasskey(ctx context.Context, passkey *domain.Passkey) error {
+	for _, p := range r.passkeys {
+		if bytes.Equal(p.CredentialID, passkey.CredentialID) {
+			return domain.ErrPasskeyExists
+		}
+	}
+	passkey.ID = int64(len(r.passkeys) + 1)
+	r.passkeys = append(r.passkeys, passkey)
+	return nil
+}
+
+func (r *fakePasskeyRepo) ListPasskeys(ctx context.Context, userID int64) ([]*domain.Passkey, error) {
+	var result []*domain.Passkey
+	for _, p := range r.passkeys {
+		if p.UserID == userID {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakePasskeyRepo) UpdatePasskeyUsage(ctx context.Context, id int64, signCount uint32, flags uint8) error {
+	for _, p := range r.passkeys {
+		if p.ID == id {
+			p.SignCount = signCount
+			p.Flags = flags
+		}
+	}
+	return nil
+}
+
+func (r *fakePasskeyRepo) CreateCeremony(ctx context.Context, ceremony *domain.PasskeyCeremony) error {
+	r.ceremonies[string(ceremony.Hash)] = ceremony
+	return nil
+}
+
+func (r *fakePasskeyRepo) ConsumeCeremony(ctx context.Context, tokenHash []byte, kind string) (*domain.PasskeyCeremony, error) {
+	ceremony, ok := r.ceremonies[string(tokenHash)]
+	if !ok || ceremony.Kind != kind {
+		return nil, domain.ErrInvalidPasskeyCeremony
+	}
+	delete(r.ceremonies, string(tokenHash))
+	return ceremony, nil
+}
+
+type fakeUserRepo struct {
+	postgres.UserRepository
+	user *pb.User
+}
+
+func (r *fakeUserRepo) GetUserByID(ctx context.Context, id int64) (*pb.User, error) {
+	if id != r.user.Id {
+		return nil, domain.ErrUserNotFound
+	}
+	return r.user, nil
+}
+
+type fakeAuthService struct {
+	IAuthService
+	user *pb.User
+}
+
+func (a *fakeAuthService) AuthorizeUser(ctx context.Context) (*pb.User, error) {
+	return a.user, nil
+}
+
+func (a *fakeAuthService) IssueTokens(ctx context.Context, user *pb.User, params domain.IssueTokensParams) (*domain.AuthTokens, error) {
+	return &domain.AuthTokens{
+		AccessToken:  &pb.Token{Userid: user.Id, Scope: params.Scope},
+		RefreshToken: &pb.Token{Userid: user.Id, Scope: ScopeRefresh},
+	}, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// письмо уходит только существующему юзверю, но ответ одинаковый - по нему нельзя перебирать email'ы
+func (a *authService) RequestPasswordReset(ctx context.Context, params domain.RequestPasswordResetParams) error {
+	const op = "service.password_reset.RequestPasswordReset"
+
+	user, err := a.userRepo.GetUserByEmail(domain.AppIDOrDefault(params.AppID), params.Email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = params.AfterFind(user)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// новый пароль по токену из письма; все сессии и токены юзверя после этого недействительны
+func (a *authService) ResetPassword(ctx context.Context, params domain.ResetPasswordParams) error {
+	const op = "service.password_reset.ResetPassword"
+
+	hashPass, err := bcrypt.GenerateFromPassword([]byte(params.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	hash := sha256.Sum256([]byte(params.Token))
+	userID, err := a.resetRepo.ResetPasswordTx(ctx, hash[:], string(hashPass))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPasswordResetToken) {
+			return domain.ErrInvalidPasswordResetToken
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// пароля в кэше нет, но там updated_at юзверя - пусть другие сервисы перечитают его
+	a.cache.Delete(userCacheKey(userID))
+
+	return nil
+}
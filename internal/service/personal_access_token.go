@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+)
+
+// токены выпускаются только из сессии юзверя: утекший токен не должен плодить новые
+func (a *authService) CreatePersonalAccessToken(ctx context.Context, params domain.CreatePersonalAccessTokenParams) (*domain.CreatedPersonalAccessToken, error) {
+	const op = "service.personal_access_token.CreatePersonalAccessToken"
+
+	info, err := a.AuthenticateToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+	if info.User == nil || info.Scope == ScopePersonalAccess {
+		return nil, domain.ErrPermissionDenied
+	}
+
+	name := strings.TrimSpace(params.Name)
+	scopes, ok := normalizeScopes(params.Scopes)
+	if name == "" || !ok || params.TTL < 0 {
+		return nil, domain.ErrInvalidPersonalAccessToken
+	}
+
+	token, err := GeneratePersonalAccessToken(info.User.Id, params.TTL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pat := &domain.PersonalAccessToken{
+		UserID: info.User.Id,
+		Name:   name,
+		Scopes: scopes,
+	}
+	if params.TTL > 0 {
+		expiresAt := token.Expiry.AsTime()
+		pat.ExpiresAt = &expiresAt
+	}
+
+	err = a.patRepo.CreatePersonalAccessToken(ctx, pat, token.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &domain.CreatedPersonalAccessToken{
+		Token:     pat,
+		PlainText: token.Plaintext,
+	}, nil
+}
+
+func (a *authService) ListPersonalAccessTokens(ctx context.Context) ([]*domain.PersonalAccessToken, error) {
+	const op = "service.personal_access_token.ListPersonalAccessTokens"
+
+	user, err := a.AuthorizeUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+
+	pats, err := a.patRepo.ListPersonalAccessTokens(ctx, user.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pats, nil
+}
+
+func (a *authService) RevokePersonalAccessToken(ctx context.Context, id int64) error {
+	const op = "service.personal_access_token.RevokePersonalAccessToken"
+
+	user, err := a.AuthorizeUser(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+
+	err = a.patRepo.DeletePersonalAccessToken(ctx, user.Id, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrPersonalAccessTokenNotFound) {
+			return domain.ErrPersonalAccessTokenNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// personal access token дает доступ от имени владельца, а его scopes отдаем вызывающим для проверки
+func (a *authService) authenticatePersonalAccessToken(ctx context.Context, token string) (*domain.TokenInfo, error) {
+	const op = "service.personal_access_token.authenticatePersonalAccessToken"
+
+	pat, user, err := a.patRepo.GetPersonalAccessToken(ctx, token)
+	if err != nil {
+		log.Printf("GetPersonalAccessToken failed: %s, error: %s", op, err)
+		return nil, errors.New("No matching user found for the given token")
+	}
+
+	err = a.patRepo.TouchPersonalAccessToken(ctx, pat.ID)
+	if err != nil {
+		log.Printf("TouchPersonalAccessToken failed: %s, error: %s", op, err)
+	}
+
+	info := &domain.TokenInfo{
+		User:     user,
+		Scope:    ScopePersonalAccess,
+		IssuedAt: pat.CreatedAt,
+		Scopes:   pat.Scopes,
+	}
+	if pat.ExpiresAt != nil {
+		info.Expiry = *pat.ExpiresAt
+	}
+
+	return info, nil
+}
+
+// scopes без пробелов (в introspection они склеиваются через пробел), без повторов и хотя бы один
+func normalizeScopes(scopes []string) ([]string, bool) {
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" || strings.ContainsAny(scope, " \t\n") {
+			return nil, false
+		}
+		result = append(result, scope)
+	}
+
+	slices.Sort(result)
+	result = slices.Compact(result)
+
+	return result, len(result) > 0
+}
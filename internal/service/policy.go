@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/pkg/policy"
+	"github.com/Iowel/app-auth-service/internal/repository/postgres"
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// как часто перечитываем политики из базы
+const policyReloadInterval = 30 * time.Second
+
+var _ IPolicyService = &policyService{}
+
+type IPolicyService interface {
+	Reload(ctx context.Context) error
+	RunReloader(ctx context.Context, waitGroup *errgroup.Group)
+	Authorize(ctx context.Context, arg domain.AuthorizeParams) (*domain.AuthorizeDecision, error)
+}
+
+type policyService struct {
+	policyRepo  postgres.PolicyRepository
+	userRepo    postgres.UserRepository
+	rbacService IRBACService
+	engine      *policy.Engine
+}
+
+func NewPolicyService(policyRepo postgres.PolicyRepository, userRepo postgres.UserRepository, rbacService IRBACService, engine *policy.Engine) IPolicyService {
+	return &policyService{
+		policyRepo:  policyRepo,
+		userRepo:    userRepo,
+		rbacService: rbacService,
+		engine:      engine,
+	}
+}
+
+// политики с ошибкой в выражении пропускаем и пишем в лог, остальные продолжают работать
+func (p *policyService) Reload(ctx context.Context) error {
+	const op = "service.policy.Reload"
+
+	policies, err := p.policyRepo.ListEnabledPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rules := make([]policy.Rule, 0, len(policies))
+	for _, pol := range policies {
+		rules = append(rules, policy.Rule{
+			Name:       pol.Name,
+			Action:     pol.Action,
+			Effect:     pol.Effect,
+			Expression: pol.Expression,
+			Priority:   pol.Priority,
+		})
+	}
+
+	for _, err := range p.engine.SetRules(rules) {
+		log.Printf("policy skipped: %s, error: %v", op, err)
+	}
+
+	return nil
+}
+
+func (p *policyService) RunReloader(ctx context.Context, waitGroup *errgroup.Group) {
+	const op = "service.policy.RunReloader"
+
+	waitGroup.Go(func() error {
+		ticker := time.NewTicker(policyReloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("policy reloader stopped")
+				return nil
+			case <-ticker.C:
+				if err := p.Reload(ctx); err != nil {
+					log.Printf("failed to reload policies, path: %s, error: %v\n", op, err)
+				}
+			}
+		}
+	})
+}
+
+func (p *policyService) Authorize(ctx context.Context, arg domain.AuthorizeParams) (*domain.AuthorizeDecision, error) {
+	const op = "service.policy.Authorize"
+
+	subject, err := p.userAttributes(ctx, arg.SubjectID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: subject: %w", op, err)
+	}
+
+	resource := map[string]any{
+		"type": arg.ResourceType,
+		"id":   arg.ResourceID,
+	}
+
+	// юзверя-ресурс раскрываем целиком, чтобы политики могли сравнивать его с subject
+	if arg.ResourceType == domain.ResourceTypeUser {
+		resourceID, err := strconv.ParseInt(arg.ResourceID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: resource id %q: %w", op, arg.ResourceID, domain.ErrUserNotFound)
+		}
+
+		resource, err = p.userAttributes(ctx, resourceID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: resource: %w", op, err)
+		}
+		resource["type"] = domain.ResourceTypeUser
+	}
+
+	decision := p.engine.Evaluate(policy.Input{
+		Subject:    subject,
+		Resource:   resource,
+		Attributes: arg.Attributes,
+		Action:     arg.Action,
+	})
+
+	return &domain.AuthorizeDecision{
+		Allowed: decision.Allowed,
+		Policy:  decision.Policy,
+		Effect:  decision.Effect,
+		Reason:  decision.Reason,
+	}, nil
+}
+
+// поля юзверя, его роли и права из RBAC и статус с кошельком из профиля
+func (p *policyService) userAttributes(ctx context.Context, userID int64) (map[string]any, error) {
+	user, err := p.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	perms, err := p.rbacService.UserPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// профиля может не быть - тогда пустой статус и нулевой кошелек
+	profile, err := p.userRepo.GetProfile(int(userID))
+	if err != nil {
+		if !errors.Is(err, domain.ErrProfileNotFound) {
+			return nil, err
+		}
+		profile = &domain.Profile{}
+	}
+
+	return userAttributes(user, perms, profile), nil
+}
+
+func userAttributes(user *pb.User, perms *domain.UserPermissions, profile *domain.Profile) map[string]any {
+	return map[string]any{
+		"id":                user.GetId(),
+		"email":             user.GetEmail(),
+		"name":              user.GetName(),
+		"role":              user.GetRole(),
+		"roles":             perms.Roles,
+		"permissions":       perms.Permissions,
+		"is_email_verified": user.GetIsemailverified(),
+		"avatar":            user.GetAvatar(),
+		"created_at":        user.GetCreatedAt().AsTime(),
+		"status":            profile.Status,
+		"wallet":            int64(profile.Wallet),
+	}
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+)
+
+type principalKey struct{}
+
+// кладем проверенный токен в контекст, чтобы сервис и хендлеры не разбирали метаданные заново
+func ContextWithPrincipal(ctx context.Context, info *domain.TokenInfo) context.Context {
+	return context.WithValue(ctx, principalKey{}, info)
+}
+
+// юзверь или сервис, которого интерцептор уже аутентифицировал для этого вызова
+func PrincipalFromContext(ctx context.Context) (*domain.TokenInfo, bool) {
+	info, ok := ctx.Value(principalKey{}).(*domain.TokenInfo)
+	return info, ok && info != nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/repository/postgres"
+	"github.com/Iowel/app-auth-service/pkg/cache"
+)
+
+// сколько живут права в кэше, если их никто не сбросил раньше
+const PermissionCacheTTL = 10 * time.Minute
+
+var _ IRBACService = &rbacService{}
+
+type IRBACService interface {
+	UserPermissions(ctx context.Context, userID int64) (*domain.UserPermissions, error)
+	// пустые role и permission не проверяются
+	Authorize(ctx context.Context, userID int64, role, permission string) error
+
+	ListRoles(ctx context.Context) ([]*domain.Role, error)
+	CreateRole(ctx context.Context, arg domain.CreateRoleParams) (*domain.Role, error)
+	DeleteRole(ctx context.Context, name string) error
+	GrantPermission(ctx context.Context, role, permission string) error
+	RevokePermission(ctx context.Context, role, permission string) error
+	AssignRole(ctx context.Context, userID int64, role string) error
+	RevokeRole(ctx context.Context, userID int64, role string) error
+}
+
+type rbacService struct {
+	rbacRepo  postgres.RBACRepository
+	cache     cache.IPermissionCache
+	userCache cache.IPostCache
+}
+
+func NewRBACService(rbacRepo postgres.RBACRepository, cache cache.IPermissionCache, userCache cache.IPostCache) IRBACService {
+	return &rbacService{
+		rbacRepo:  rbacRepo,
+		cache:     cache,
+		userCache: userCache,
+	}
+}
+
+// сначала кэш; redis недоступен - идем в базу
+func (r *rbacService) UserPermissions(ctx context.Context, userID int64) (*domain.UserPermissions, error) {
+	const op = "service.rbac.UserPermissions"
+
+	perms, err := r.cache.Get(ctx, userID)
+	if err != nil {
+		log.Printf("permission cache read failed: %s, error: %s", op, err)
+	}
+	if perms != nil {
+		return perms, nil
+	}
+
+	perms, err = r.rbacRepo.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := r.cache.Set(ctx, perms); err != nil {
+		log.Printf("permission cache write failed: %s, error: %s", op, err)
+	}
+
+	return perms, nil
+}
+
+func (r *rbacService) Authorize(ctx context.Context, userID int64, role, permission string) error {
+	const op = "service.rbac.Authorize"
+
+	perms, err := r.UserPermissions(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if role != "" && !perms.HasRole(role) {
+		return domain.ErrPermissionDenied
+	}
+	if permission != "" && !perms.HasPermission(permission) {
+		return domain.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+func (r *rbacService) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	const op = "service.rbac.ListRoles"
+
+	roles, err := r.rbacRepo.ListRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, nil
+}
+
+func (r *rbacService) CreateRole(ctx context.Context, arg domain.CreateRoleParams) (*domain.Role, error) {
+	const op = "service.rbac.CreateRole"
+
+	arg.Name = strings.TrimSpace(arg.Name)
+	if arg.Name == "" || slices.ContainsFunc(arg.Permissions, func(p string) bool { return strings.TrimSpace(p) == "" }) {
+		return nil, domain.ErrInvalidRoleName
+	}
+
+	role, err := r.rbacRepo.CreateRoleTx(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return role, nil
+}
+
+func (r *rbacService) DeleteRole(ctx context.Context, name string) error {
+	const op = "service.rbac.DeleteRole"
+
+	userIDs, err := r.rbacRepo.DeleteRoleTx(ctx, name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.invalidate(ctx, userIDs...)
+	r.invalidateUsers(userIDs...)
+	return nil
+}
+
+func (r *rbacService) GrantPermission(ctx context.Context, role, permission string) error {
+	const op = "service.rbac.GrantPermission"
+
+	permission = strings.TrimSpace(permission)
+	if permission == "" {
+		return domain.ErrInvalidRoleName
+	}
+
+	userIDs, err := r.rbacRepo.GrantPermissionTx(ctx, role, permission)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.invalidate(ctx, userIDs...)
+	return nil
+}
+
+func (r *rbacService) RevokePermission(ctx context.Context, role, permission string) error {
+	const op = "service.rbac.RevokePermission"
+
+	userIDs, err := r.rbacRepo.RevokePermission(ctx, role, permission)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.invalidate(ctx, userIDs...)
+	return nil
+}
+
+func (r *rbacService) AssignRole(ctx context.Context, userID int64, role string) error {
+	const op = "service.rbac.AssignRole"
+
+	if err := r.rbacRepo.AssignRoleTx(ctx, userID, role); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.invalidate(ctx, userID)
+	r.invalidateUsers(userID)
+	return nil
+}
+
+func (r *rbacService) RevokeRole(ctx context.Context, userID int64, role string) error {
+	const op = "service.rbac.RevokeRole"
+
+	if err := r.rbacRepo.RevokeRoleTx(ctx, userID, role); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.invalidate(ctx, userID)
+	r.invalidateUsers(userID)
+	return nil
+}
+
+// не получилось сбросить - права обновятся сами через PermissionCacheTTL
+func (r *rbacService) invalidate(ctx context.Context, userIDs ...int64) {
+	if err := r.cache.Delete(ctx, userIDs...); err != nil {
+		log.Printf("permission cache invalidation failed: users %v, error: %s", userIDs, err)
+	}
+}
+
+// состав ролей юзверя поменялся - в кэше юзверя лежит старая users.role
+func (r *rbacService) invalidateUsers(userIDs ...int64) {
+	for _, id := range userIDs {
+		r.userCache.Delete(userCacheKey(id))
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/pkg/pb"
+)
+
+// список активных сессий юзверя и id текущей сессии
+func (a *authService) ListSessions(ctx context.Context) ([]*domain.Session, string, error) {
+	const op = "service.session.ListSessions"
+
+	user, current, err := a.currentSession(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sessions, err := a.sessionRepo.ListSessions(ctx, user.Id)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, current.ID, nil
+}
+
+func (a *authService) RevokeSession(ctx context.Context, sessionID string) error {
+	const op = "service.session.RevokeSession"
+
+	user, _, err := a.currentSession(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = a.sessionRepo.DeleteSession(ctx, user.Id, sessionID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return domain.ErrSessionNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// разлогиниваем все девайсы, кроме текущего
+func (a *authService) RevokeAllOtherSessions(ctx context.Context) (int64, error) {
+	const op = "service.session.RevokeAllOtherSessions"
+
+	user, current, err := a.currentSession(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked, err := a.sessionRepo.DeleteOtherSessions(ctx, user.Id, current.ID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revoked, nil
+}
+
+// юзверь и сессия, к которой привязан токен из запроса
+func (a *authService) currentSession(ctx context.Context) (*pb.User, *domain.Session, error) {
+	const op = "service.session.currentSession"
+
+	user, err := a.AuthorizeUser(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+
+	accessToken, err := accessTokenFromContext(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+
+	// токены, выданные до появления сессий, к сессии не привязаны
+	session, err := a.sessionRepo.GetSessionForToken(ctx, accessToken)
+	if err != nil {
+		log.Printf("GetSessionForToken failed: %s, error: %s", op, err)
+		return nil, nil, fmt.Errorf("%w: token is not bound to a session", domain.ErrUnauthenticated)
+	}
+
+	return user, session, nil
+}
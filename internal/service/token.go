@@ -4,8 +4,11 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base32"
+	"strconv"
 	"time"
 
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/pkg/jwtauth"
 	"github.com/Iowel/app-auth-service/pkg/pb"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -13,6 +16,22 @@ import (
 
 const (
 	ScopeAuthentication = "authentication"
+	ScopeRefresh        = "refresh"
+	// personal access token; его права - в scopes, которые выбрал юзверь
+	ScopePersonalAccess = "personal_access"
+
+	// scopes, которые могут запросить OAuth клиенты
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+	IDTokenTTL      = time.Hour
+	// refresh токена у сервиса нет, по истечении он просто запрашивает новый
+	ServiceTokenTTL = time.Hour
 )
 
 // генерируем токен с заданным сроком действия
@@ -38,3 +57,64 @@ func GenerateToken(userID int64, ttl time.Duration, scope string) (*pb.Token, er
 	token.Hash = hash[:]
 	return token, nil
 }
+
+// тот же случайный токен, но с префиксом, по которому AuthorizeUser узнает personal access token
+func GeneratePersonalAccessToken(userID int64, ttl time.Duration) (*pb.Token, error) {
+	return generatePrefixedToken(domain.PersonalAccessTokenPrefix, userID, ttl, ScopePersonalAccess)
+}
+
+// токен client_credentials; юзверя нет, поэтому userID нулевой
+func GenerateServiceToken(ttl time.Duration, scope string) (*pb.Token, error) {
+	return generatePrefixedToken(domain.ServiceTokenPrefix, 0, ttl, scope)
+}
+
+func generatePrefixedToken(prefix string, userID int64, ttl time.Duration, scope string) (*pb.Token, error) {
+	token, err := GenerateToken(userID, ttl, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Plaintext = prefix + token.Plaintext
+	hash := sha256.Sum256([]byte(token.Plaintext))
+	token.Hash = hash[:]
+
+	return token, nil
+}
+
+// генерируем подписанный JWT, который можно проверить без похода в базу
+func GenerateSignedToken(signer *jwtauth.Manager, user *pb.User, ttl time.Duration, scope, sessionID, clientID string) (*pb.Token, error) {
+	claims := &jwtauth.Claims{
+		Role:             user.Role,
+		Scope:            scope,
+		SessionID:        sessionID,
+		ClientID:         clientID,
+		AppID:            user.AppId,
+		RegisteredClaims: jwtauth.NewClaims(strconv.FormatInt(user.Id, 10), ttl),
+	}
+
+	// jti делает каждый токен уникальным
+	randomBytes := make([]byte, 16)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return nil, err
+	}
+	claims.ID = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(randomBytes)
+
+	plaintext, err := signer.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// хеш храним в базе так же, как и для обычного токена - для отзыва и VerifyToken
+	hash := sha256.Sum256([]byte(plaintext))
+
+	return &pb.Token{
+		Plaintext: plaintext,
+		Userid:    user.Id,
+		Hash:      hash[:],
+		Expiry:    timestamppb.New(claims.ExpiresAt.Time),
+		Scope:     scope,
+		Role:      user.Role,
+		AppId:     user.AppId,
+	}, nil
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/Iowel/app-auth-service/internal/pkg/totp"
+	"github.com/Iowel/app-auth-service/pkg/pb"
+	"github.com/Iowel/app-auth-service/pkg/util"
+)
+
+const (
+	// сколько живет challenge между вводом пароля и вводом кода
+	TwoFactorChallengeTTL = 5 * time.Minute
+
+	// после стольких неверных кодов challenge сгорает, нужно заново вводить пароль
+	maxTwoFactorAttempts = 5
+
+	recoveryCodesCount = 10
+
+	defaultTOTPIssuer = "app-auth-service"
+)
+
+// начинаем подключение 2FA: секрет и otpauth:// ссылка для QR-кода
+func (a *authService) EnrollTwoFactor(ctx context.Context) (*domain.TwoFactorEnrollment, error) {
+	const op = "service.two_factor.EnrollTwoFactor"
+
+	user, err := a.AuthorizeUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	encrypted, err := util.Encrypt(a.secret, []byte(secret))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = a.twoFactorRepo.UpsertTOTP(ctx, user.Id, encrypted)
+	if err != nil {
+		if errors.Is(err, domain.ErrTwoFactorAlreadyEnabled) {
+			return nil, domain.ErrTwoFactorAlreadyEnabled
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	issuer := a.totpIssuer
+	if issuer == "" {
+		issuer = defaultTOTPIssuer
+	}
+
+	return &domain.TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(issuer, user.Email, secret),
+	}, nil
+}
+
+// первый верный код включает 2FA; recovery коды показываем один раз, в базе только хеши
+func (a *authService) ConfirmTwoFactor(ctx context.Context, code string) ([]string, error) {
+	const op = "service.two_factor.ConfirmTwoFactor"
+
+	user, err := a.AuthorizeUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+
+	userTOTP, err := a.twoFactorRepo.GetTOTP(ctx, user.Id)
+	if err != nil {
+		if errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+			return nil, domain.ErrTwoFactorNotEnabled
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if userTOTP.IsConfirmed {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := util.Decrypt(a.secret, userTOTP.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	step, ok := totp.Validate(string(secret), normalizeTwoFactorCode(code), time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([][]byte, 0, recoveryCodesCount)
+	for range recoveryCodesCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		hash := sha256.Sum256([]byte(normalizeTwoFactorCode(code)))
+
+		codes = append(codes, code)
+		hashes = append(hashes, hash[:])
+	}
+
+	err = a.twoFactorRepo.ConfirmTOTPTx(ctx, user.Id, step, hashes)
+	if err != nil {
+		if errors.Is(err, domain.ErrTwoFactorAlreadyEnabled) {
+			return nil, domain.ErrTwoFactorAlreadyEnabled
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return codes, nil
+}
+
+// второй шаг логина: challenge из LoginUser + код из приложения или recovery код
+func (a *authService) VerifyTwoFactor(ctx context.Context, params domain.VerifyTwoFactorParams) (*domain.AuthTokens, error) {
+	const op = "service.two_factor.VerifyTwoFactor"
+
+	hash := sha256.Sum256([]byte(params.ChallengeToken))
+	challenge, err := a.twoFactorRepo.ClaimChallengeAttempt(ctx, hash[:], maxTwoFactorAttempts)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTwoFactorChallenge) {
+			return nil, domain.ErrInvalidTwoFactorChallenge
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.userRepo.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// второй фактор под тем же лимитом, что и пароль: challenge'и можно брать пачками
+	err = a.checkLoginThrottle(ctx, user.AppId, user.Email, challenge.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := a.checkSecondFactor(ctx, challenge.UserID, params.Code)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		a.registerLoginFailure(ctx, user.AppId, user.Email, challenge.ClientIP)
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	consumed, err := a.twoFactorRepo.ConsumeChallenge(ctx, challenge.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !consumed {
+		return nil, domain.ErrInvalidTwoFactorChallenge
+	}
+	// счетчик неудач обнуляем только после обоих факторов
+	a.resetLoginFailures(ctx, user.AppId, user.Email)
+
+	// сессия получает девайс, с которого вводили пароль
+	tokens, err := a.IssueTokens(ctx, user, domain.IssueTokensParams{
+		Scope:     ScopeAuthentication,
+		UserAgent: challenge.UserAgent,
+		ClientIP:  challenge.ClientIP,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+// выключить 2FA можно только предъявив действующий код
+func (a *authService) DisableTwoFactor(ctx context.Context, code string) error {
+	const op = "service.two_factor.DisableTwoFactor"
+
+	user, err := a.AuthorizeUser(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrUnauthenticated, err)
+	}
+
+	ok, err := a.checkSecondFactor(ctx, user.Id, code)
+	if err != nil {
+		if errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+			return domain.ErrTwoFactorNotEnabled
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	err = a.twoFactorRepo.DeleteTwoFactorTx(ctx, user.Id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// включена ли у юзверя подтвержденная 2FA
+func (a *authService) twoFactorEnabled(ctx context.Context, userID int64) (bool, error) {
+	userTOTP, err := a.twoFactorRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return userTOTP.IsConfirmed, nil
+}
+
+// пароль верный, но токены выдадим только после второго фактора
+func (a *authService) createTwoFactorChallenge(ctx context.Context, user *pb.User, userAgent, clientIP string) (string, error) {
+	token, err := GenerateToken(user.Id, TwoFactorChallengeTTL, "")
+	if err != nil {
+		return "", err
+	}
+
+	err = a.twoFactorRepo.CreateChallenge(ctx, &domain.TwoFactorChallenge{
+		Hash:      token.Hash,
+		UserID:    user.Id,
+		UserAgent: userAgent,
+		ClientIP:  clientIP,
+		ExpiredAt: token.Expiry.AsTime(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token.Plaintext, nil
+}
+
+// 6 цифр - TOTP код, иначе пробуем как recovery код
+func (a *authService) checkSecondFactor(ctx context.Context, userID int64, code string) (bool, error) {
+	userTOTP, err := a.twoFactorRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !userTOTP.IsConfirmed {
+		return false, domain.ErrTwoFactorNotEnabled
+	}
+
+	code = normalizeTwoFactorCode(code)
+
+	if len(code) == totp.Digits {
+		secret, err := util.Decrypt(a.secret, userTOTP.Secret)
+		if err != nil {
+			return false, err
+		}
+
+		step, ok := totp.Validate(string(secret), code, time.Now())
+		if !ok {
+			return false, nil
+		}
+
+		// один и тот же код дважды не принимаем
+		return a.twoFactorRepo.UseTOTPStep(ctx, userID, step)
+	}
+
+	hash := sha256.Sum256([]byte(code))
+	return a.twoFactorRepo.UseRecoveryCode(ctx, userID, hash[:])
+}
+
+// recovery код вида xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	randomBytes := make([]byte, 7)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(randomBytes))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// юзверь может ввести код с пробелами, дефисами и в любом регистре
+func normalizeTwoFactorCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return code
+}
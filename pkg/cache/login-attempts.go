@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// счетчики неудачных логинов и ключи блокировок с TTL
+type ILoginAttempts interface {
+	// +1 к счетчику; окно отсчитывается от первой неудачи
+	Fail(ctx context.Context, key string, window time.Duration) (int64, error)
+	// ставит ключ блокировки на ttl
+	Block(ctx context.Context, key string, ttl time.Duration) error
+	// сколько еще действует самая долгая из блокировок; 0 - ни одной нет
+	Blocked(ctx context.Context, keys ...string) (time.Duration, error)
+	Reset(ctx context.Context, keys ...string) error
+}
+
+type redisLoginAttempts struct {
+	client *redis.Client
+}
+
+func NewLoginAttempts(host string, db int) ILoginAttempts {
+	return &redisLoginAttempts{
+		client: redis.NewClient(&redis.Options{
+			Addr:     host,
+			Password: "",
+			DB:       db,
+		}),
+	}
+}
+
+func (l *redisLoginAttempts) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	pipe := l.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	// NX: окно не продлевается каждой новой попыткой
+	pipe.ExpireNX(ctx, key, window)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (l *redisLoginAttempts) Block(ctx context.Context, key string, ttl time.Duration) error {
+	return l.client.Set(ctx, key, 1, ttl).Err()
+}
+
+func (l *redisLoginAttempts) Blocked(ctx context.Context, keys ...string) (time.Duration, error) {
+	pipe := l.client.Pipeline()
+	ttls := make([]*redis.DurationCmd, 0, len(keys))
+	for _, key := range keys {
+		ttls = append(ttls, pipe.PTTL(ctx, key))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	// у отсутствующего ключа TTL отрицательный
+	var longest time.Duration
+	for _, ttl := range ttls {
+		longest = max(longest, ttl.Val())
+	}
+
+	return longest, nil
+}
+
+func (l *redisLoginAttempts) Reset(ctx context.Context, keys ...string) error {
+	return l.client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Iowel/app-auth-service/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// роли и права юзверя, собранные из базы; сбрасываются при любом изменении его ролей
+type IPermissionCache interface {
+	// nil без ошибки - в кэше нет
+	Get(ctx context.Context, userID int64) (*domain.UserPermissions, error)
+	Set(ctx context.Context, perms *domain.UserPermissions) error
+	Delete(ctx context.Context, userIDs ...int64) error
+}
+
+type redisPermissionCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewPermissionCache(host string, db int, ttl time.Duration) IPermissionCache {
+	return &redisPermissionCache{
+		client: redis.NewClient(&redis.Options{
+			Addr:     host,
+			Password: "",
+			DB:       db,
+		}),
+		ttl: ttl,
+	}
+}
+
+func (c *redisPermissionCache) Get(ctx context.Context, userID int64) (*domain.UserPermissions, error) {
+	val, err := c.client.Get(ctx, permissionsKey(userID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var perms domain.UserPermissions
+	if err := json.Unmarshal(val, &perms); err != nil {
+		return nil, err
+	}
+
+	return &perms, nil
+}
+
+func (c *redisPermissionCache) Set(ctx context.Context, perms *domain.UserPermissions) error {
+	val, err := json.Marshal(perms)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, permissionsKey(perms.UserID), val, c.ttl).Err()
+}
+
+func (c *redisPermissionCache) Delete(ctx context.Context, userIDs ...int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		keys = append(keys, permissionsKey(id))
+	}
+
+	return c.client.Del(ctx, keys...).Err()
+}
+
+func permissionsKey(userID int64) string {
+	return "permissions:" + strconv.FormatInt(userID, 10)
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// скользящее окно на sorted set: каждый запрос - элемент с временем в score
+type IRateLimiter interface {
+	// учитывает запрос, если лимит не исчерпан; иначе возвращает, через сколько освободится место
+	Allow(ctx context.Context, key string, limit int64, window time.Duration) (time.Duration, error)
+	// то же, но запрос не учитывает: для счетчиков, которые растут только на неудачах
+	RetryAfter(ctx context.Context, key string, limit int64, window time.Duration) (time.Duration, error)
+}
+
+type redisRateLimiter struct {
+	client *redis.Client
+}
+
+func NewRateLimiter(host string, db int) IRateLimiter {
+	return &redisRateLimiter{
+		client: redis.NewClient(&redis.Options{
+			Addr:     host,
+			Password: "",
+			DB:       db,
+		}),
+	}
+}
+
+// одним скриптом, иначе параллельные запросы проскочат между ZCARD и ZADD
+// KEYS[1] - ключ, ARGV: сейчас (мс), окно (мс), лимит, уникальный элемент
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+
+if redis.call('ZCARD', KEYS[1]) < tonumber(ARGV[3]) then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	return 0
+end
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return math.max(tonumber(oldest[2]) + window - now, 1)
+`)
+
+// KEYS[1] - ключ, ARGV: сейчас (мс), окно (мс), лимит
+var retryAfterScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+
+if redis.call('ZCARD', KEYS[1]) < tonumber(ARGV[3]) then
+	return 0
+end
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return math.max(tonumber(oldest[2]) + window - now, 1)
+`)
+
+func (l *redisRateLimiter) Allow(ctx context.Context, key string, limit int64, window time.Duration) (time.Duration, error) {
+	member, err := randomMember()
+	if err != nil {
+		return 0, err
+	}
+
+	retryAfter, err := slidingWindowScript.Run(ctx, l.client, []string{key},
+		time.Now().UnixMilli(), window.Milliseconds(), limit, member).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(retryAfter) * time.Millisecond, nil
+}
+
+// два запроса в одну миллисекунду не должны схлопнуться в один элемент
+func randomMember() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (l *redisRateLimiter) RetryAfter(ctx context.Context, key string, limit int64, window time.Duration) (time.Duration, error) {
+	retryAfter, err := retryAfterScript.Run(ctx, l.client, []string{key},
+		time.Now().UnixMilli(), window.Milliseconds(), limit).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(retryAfter) * time.Millisecond, nil
+}
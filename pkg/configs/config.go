@@ -3,6 +3,9 @@ package configs
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +17,8 @@ type Config struct {
 	Redis     Redis
 	Web       WebConfig
 	SmtpGmail SmtpGmail
+	WebAuthn  WebAuthnConfig
+	RateLimit RateLimitConfig
 }
 
 type WebConfig struct {
@@ -39,6 +44,60 @@ type Dbconfig struct {
 type AuthConfig struct {
 	Secret   string
 	TokenTTL string
+
+	// opaque (по умолчанию) или jwt
+	TokenFormat string
+	Issuer      string
+
+	// client_id -> client_secret тех, кому разрешен /oauth/introspect
+	IntrospectionClients map[string]string
+
+	// страница логина фронтенда, куда /oauth/authorize отправляет незалогиненного юзверя
+	LoginURL string
+
+	// имя сервиса, которое увидит юзверь в приложении-аутентификаторе
+	TOTPIssuer string
+
+	// страница фронтенда с формой нового пароля, ссылка на нее уходит в письме
+	PasswordResetURL string
+
+	// страница фронтенда, куда ведет ссылка для входа без пароля
+	MagicLinkURL string
+
+	// блокировка после неудачных логинов; 0 - значения по умолчанию
+	LoginMaxFailures      int64
+	LoginMaxFailuresPerIP int64
+	LoginLockoutDuration  time.Duration
+}
+
+const (
+	TokenFormatOpaque = "opaque"
+	TokenFormatJWT    = "jwt"
+)
+
+// relying party для passkeys; пустой RPID - passkeys выключены
+type WebAuthnConfig struct {
+	RPID          string
+	RPDisplayName string
+	RPOrigins     []string
+}
+
+// лимит запросов: не больше Limit за Window
+type RateLimit struct {
+	Limit  int64
+	Window time.Duration
+}
+
+// лимиты одного метода; нулевой Limit - по этому ключу не ограничиваем
+type MethodRateLimit struct {
+	PerIP    RateLimit
+	PerEmail RateLimit
+}
+
+type RateLimitConfig struct {
+	// имя метода (LoginUser) -> лимиты, перекрывают значения по умолчанию.
+	// AuthFailures и ForwardAuth - лимиты неудачных аутентификаций, из них берется только ip
+	Methods map[string]MethodRateLimit
 }
 
 type Redis struct {
@@ -60,9 +119,95 @@ func LoadConfig() *Config {
 	return &Config{
 		DB:        Dbconfig{Dsn: os.Getenv("DSN")},
 		Web:       WebConfig{Port: os.Getenv("HTTP_PORT"), Dsn: os.Getenv("DSN"), Env: os.Getenv("ENV"), AllowedOrigins: os.Getenv("ALLOWED_ORIGINS"), Frontend_port: os.Getenv("FRONTEND_PORT"), Backend_port: os.Getenv("BACKEND_PORT"), ServerAPI: os.Getenv("SERVRER_API")},
-		Auth:      AuthConfig{Secret: os.Getenv("SECRET"), TokenTTL: os.Getenv("TOKENTTL")},
+		Auth:      AuthConfig{Secret: os.Getenv("SECRET"), TokenTTL: os.Getenv("TOKENTTL"), TokenFormat: os.Getenv("TOKEN_FORMAT"), Issuer: os.Getenv("TOKEN_ISSUER"), IntrospectionClients: parseClients(os.Getenv("INTROSPECTION_CLIENTS")), LoginURL: os.Getenv("OAUTH_LOGIN_URL"), TOTPIssuer: os.Getenv("TOTP_ISSUER"), PasswordResetURL: os.Getenv("PASSWORD_RESET_URL"), MagicLinkURL: os.Getenv("MAGIC_LINK_URL"), LoginMaxFailures: parseInt(os.Getenv("LOGIN_MAX_FAILURES")), LoginMaxFailuresPerIP: parseInt(os.Getenv("LOGIN_MAX_FAILURES_PER_IP")), LoginLockoutDuration: parseDuration(os.Getenv("LOGIN_LOCKOUT_DURATION"))},
 		Grpc:      Grpc{Port: os.Getenv("GRPC_SERVER_ADDRESS")},
 		Redis:     Redis{Port: os.Getenv("REDIS_PORT")},
 		SmtpGmail: SmtpGmail{SenderName: os.Getenv("EMAIL_SENDER_NAME"), SenderAddress: os.Getenv("EMAIL_SENDER_ADDRESS"), SenderPassword: os.Getenv("EMAIL_SENDER_PASSWORD")},
+		WebAuthn:  WebAuthnConfig{RPID: os.Getenv("WEBAUTHN_RP_ID"), RPDisplayName: os.Getenv("WEBAUTHN_RP_NAME"), RPOrigins: parseList(os.Getenv("WEBAUTHN_RP_ORIGINS"))},
+		RateLimit: RateLimitConfig{Methods: parseRateLimits(os.Getenv("RATE_LIMITS"))},
+	}
+}
+
+// формат: "client_id:secret,client_id2:secret2"
+func parseClients(value string) map[string]string {
+	clients := make(map[string]string)
+
+	for _, pair := range strings.Split(value, ",") {
+		id, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || id == "" || secret == "" {
+			continue
+		}
+		clients[id] = secret
+	}
+
+	return clients
+}
+
+// формат: "https://example.com,https://app.example.com"
+func parseList(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// пустое или кривое значение - 0, дальше сработает значение по умолчанию
+func parseInt(value string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// формат time.ParseDuration: "15m", "1h30m"
+func parseDuration(value string) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0
 	}
+	return d
+}
+
+// формат: "LoginUser=ip:30/1m,email:10/1m;RegisterUser=ip:10/1h;AuthFailures=ip:60/1m"
+func parseRateLimits(value string) map[string]MethodRateLimit {
+	methods := make(map[string]MethodRateLimit)
+
+	for _, entry := range strings.Split(value, ";") {
+		method, rules, ok := strings.Cut(entry, "=")
+		method = strings.TrimSpace(method)
+		if !ok || method == "" {
+			continue
+		}
+
+		var limits MethodRateLimit
+		for _, rule := range strings.Split(rules, ",") {
+			key, limit, ok := strings.Cut(strings.TrimSpace(rule), ":")
+			if !ok {
+				continue
+			}
+
+			count, window, ok := strings.Cut(limit, "/")
+			if !ok {
+				continue
+			}
+
+			rateLimit := RateLimit{Limit: parseInt(count), Window: parseDuration(window)}
+			switch key {
+			case "ip":
+				limits.PerIP = rateLimit
+			case "email":
+				limits.PerEmail = rateLimit
+			}
+		}
+
+		methods[method] = limits
+	}
+
+	return methods
 }
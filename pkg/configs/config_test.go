@@ -0,0 +1,64 @@
+package configs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  map[string]MethodRateLimit
+	}{
+		{
+			name:  "empty",
+			value: "",
+			want:  map[string]MethodRateLimit{},
+		},
+		{
+			name:  "several methods",
+			value: "LoginUser=ip:30/1m,email:10/1m;RegisterUser=ip:10/1h",
+			want: map[string]MethodRateLimit{
+				"LoginUser": {
+					PerIP:    RateLimit{Limit: 30, Window: time.Minute},
+					PerEmail: RateLimit{Limit: 10, Window: time.Minute},
+				},
+				"RegisterUser": {
+					PerIP: RateLimit{Limit: 10, Window: time.Hour},
+				},
+			},
+		},
+		{
+			name:  "spaces and trailing separators",
+			value: " LoginUser = ip: 5 / 30s , email:2/1h30m ; ",
+			want: map[string]MethodRateLimit{
+				"LoginUser": {
+					PerIP:    RateLimit{Limit: 5, Window: 30 * time.Second},
+					PerEmail: RateLimit{Limit: 2, Window: 90 * time.Minute},
+				},
+			},
+		},
+		{
+			// кривые правила дают нули, метод при этом все равно попадает в конфиг
+			name:  "malformed rules",
+			value: "LoginUser=ip:abc/1m,email:10/forever,device:1/1m,ip;=ip:1/1m;VerifyEmail",
+			want: map[string]MethodRateLimit{
+				"LoginUser": {
+					PerIP:    RateLimit{Limit: 0, Window: time.Minute},
+					PerEmail: RateLimit{Limit: 10, Window: 0},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseRateLimits(tt.value)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRateLimits(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
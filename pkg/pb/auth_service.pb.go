@@ -25,46 +25,214 @@ var File_auth_service_proto protoreflect.FileDescriptor
 
 const file_auth_service_proto_rawDesc = "" +
 	"\n" +
-	"\x12auth_service.proto\x12\x02pb\x1a\x17rpc_register_user.proto\x1a\x14rpc_login_user.proto\x1a\x15rpc_update_user.proto\x1a\x16rpc_verify_email.proto\x1a\x16rpc_verify_token.proto\x1a\x15rpc_verify_role.proto\x1a\x1cgoogle/api/annotations.proto2\xb2\x04\n" +
+	"\x12auth_service.proto\x12\x02pb\x1a\x17rpc_register_user.proto\x1a\x14rpc_login_user.proto\x1a\x15rpc_update_user.proto\x1a\x16rpc_verify_email.proto\x1a\x16rpc_verify_token.proto\x1a\x15rpc_verify_role.proto\x1a\x17rpc_refresh_token.proto\x1a\x11rpc_session.proto\x1a\x10rpc_logout.proto\x1a\x1crpc_rotate_signing_key.proto\x1a\x14rpc_two_factor.proto\x1a\x11rpc_passkey.proto\x1a\x18rpc_password_reset.proto\x1a\x14rpc_magic_link.proto\x1a\x18rpc_unlock_account.proto\x1a\x0erpc_rbac.proto\x1a\x13rpc_authorize.proto\x1a\x1frpc_personal_access_token.proto\x1a\x18rpc_service_client.proto\x1a\x1cgoogle/api/annotations.proto2\xd6\"\n" +
 	"\vAuthService\x12b\n" +
 	"\fRegisterUser\x12\x17.pb.RegisterUserRequest\x1a\x1b.pb.RegisterResponsePayload\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/register_user\x12V\n" +
-	"\tLoginUser\x12\x14.pb.LoginUserRequest\x1a\x18.pb.LoginResponsePayload\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/login_user\x12W\n" +
+	"\tLoginUser\x12\x14.pb.LoginUserRequest\x1a\x18.pb.LoginResponsePayload\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/login_user\x12_\n" +
+	"\fRefreshToken\x12\x17.pb.RefreshTokenRequest\x1a\x18.pb.RefreshTokenResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/refresh_token\x12F\n" +
+	"\x06Logout\x12\x11.pb.LogoutRequest\x1a\x12.pb.LogoutResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/v1/logout\x12W\n" +
 	"\n" +
 	"UpdateUser\x12\x15.pb.UpdateUserRequest\x1a\x16.pb.UpdateUserResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*2\x0f/v1/update_user\x12X\n" +
 	"\vVerifyEmail\x12\x16.pb.VerifyEmailRequest\x1a\x17.pb.VerifyEmailResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/verify_email\x12[\n" +
 	"\vVerifyToken\x12\x16.pb.VerifyTokenRequest\x1a\x17.pb.VerifyTokenResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/verify_token\x12W\n" +
 	"\n" +
-	"VerifyRole\x12\x15.pb.VerifyRoleRequest\x1a\x16.pb.VerifyRoleResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/verify_roleB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
+	"VerifyRole\x12\x15.pb.VerifyRoleRequest\x1a\x16.pb.VerifyRoleResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/verify_role\x12W\n" +
+	"\fListSessions\x12\x17.pb.ListSessionsRequest\x1a\x18.pb.ListSessionsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/sessions\x12g\n" +
+	"\rRevokeSession\x12\x18.pb.RevokeSessionRequest\x1a\x19.pb.RevokeSessionResponse\"!\x82\xd3\xe4\x93\x02\x1b*\x19/v1/sessions/{session_id}\x12\x86\x01\n" +
+	"\x16RevokeAllOtherSessions\x12!.pb.RevokeAllOtherSessionsRequest\x1a\".pb.RevokeAllOtherSessionsResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/sessions/revoke_others\x12v\n" +
+	"\x10RotateSigningKey\x12\x1b.pb.RotateSigningKeyRequest\x1a\x1c.pb.RotateSigningKeyResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/admin/rotate_signing_key\x12e\n" +
+	"\x0fEnrollTwoFactor\x12\x1a.pb.EnrollTwoFactorRequest\x1a\x1b.pb.EnrollTwoFactorResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/2fa/enroll\x12i\n" +
+	"\x10ConfirmTwoFactor\x12\x1b.pb.ConfirmTwoFactorRequest\x1a\x1c.pb.ConfirmTwoFactorResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/2fa/confirm\x12e\n" +
+	"\x0fVerifyTwoFactor\x12\x1a.pb.VerifyTwoFactorRequest\x1a\x1b.pb.VerifyTwoFactorResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/2fa/verify\x12i\n" +
+	"\x10DisableTwoFactor\x12\x1b.pb.DisableTwoFactorRequest\x1a\x1c.pb.DisableTwoFactorResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/2fa/disable\x12\x8d\x01\n" +
+	"\x18BeginPasskeyRegistration\x12#.pb.BeginPasskeyRegistrationRequest\x1a$.pb.BeginPasskeyRegistrationResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/passkeys/register/begin\x12\x91\x01\n" +
+	"\x19FinishPasskeyRegistration\x12$.pb.FinishPasskeyRegistrationRequest\x1a%.pb.FinishPasskeyRegistrationResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/passkeys/register/finish\x12u\n" +
+	"\x11BeginPasskeyLogin\x12\x1c.pb.BeginPasskeyLoginRequest\x1a\x1d.pb.BeginPasskeyLoginResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/passkeys/login/begin\x12y\n" +
+	"\x12FinishPasskeyLogin\x12\x1d.pb.FinishPasskeyLoginRequest\x1a\x1e.pb.FinishPasskeyLoginResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/passkeys/login/finish\x12y\n" +
+	"\x14RequestPasswordReset\x12\x1f.pb.RequestPasswordResetRequest\x1a .pb.RequestPasswordResetResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/password/forgot\x12c\n" +
+	"\rResetPassword\x12\x18.pb.ResetPasswordRequest\x1a\x19.pb.ResetPasswordResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/password/reset\x12p\n" +
+	"\x10RequestMagicLink\x12\x1b.pb.RequestMagicLinkRequest\x1a\x1c.pb.RequestMagicLinkResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/magic_link/request\x12p\n" +
+	"\x10ConsumeMagicLink\x12\x1b.pb.ConsumeMagicLinkRequest\x1a\x1c.pb.ConsumeMagicLinkResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/magic_link/consume\x12i\n" +
+	"\rUnlockAccount\x12\x18.pb.UnlockAccountRequest\x1a\x19.pb.UnlockAccountResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/admin/unlock_account\x12Q\n" +
+	"\tListRoles\x12\x14.pb.ListRolesRequest\x1a\x15.pb.ListRolesResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/admin/roles\x12W\n" +
+	"\n" +
+	"CreateRole\x12\x15.pb.CreateRoleRequest\x1a\x16.pb.CreateRoleResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/admin/roles\x12[\n" +
+	"\n" +
+	"DeleteRole\x12\x15.pb.DeleteRoleRequest\x1a\x16.pb.DeleteRoleResponse\"\x1e\x82\xd3\xe4\x93\x02\x18*\x16/v1/admin/roles/{name}\x12\x85\x01\n" +
+	"\x13GrantRolePermission\x12\x1e.pb.GrantRolePermissionRequest\x1a\x1f.pb.GrantRolePermissionResponse\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/v1/admin/roles/{role}/permissions\x12\x92\x01\n" +
+	"\x14RevokeRolePermission\x12\x1f.pb.RevokeRolePermissionRequest\x1a .pb.RevokeRolePermissionResponse\"7\x82\xd3\xe4\x93\x021*//v1/admin/roles/{role}/permissions/{permission}\x12s\n" +
+	"\x0eAssignUserRole\x12\x19.pb.AssignUserRoleRequest\x1a\x1a.pb.AssignUserRoleResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/admin/users/{user_id}/roles\x12w\n" +
+	"\x0eRevokeUserRole\x12\x19.pb.RevokeUserRoleRequest\x1a\x1a.pb.RevokeUserRoleResponse\".\x82\xd3\xe4\x93\x02(*&/v1/admin/users/{user_id}/roles/{role}\x12\x82\x01\n" +
+	"\x12GetUserPermissions\x12\x1d.pb.GetUserPermissionsRequest\x1a\x1e.pb.GetUserPermissionsResponse\"-\x82\xd3\xe4\x93\x02'\x12%/v1/admin/users/{user_id}/permissions\x12R\n" +
+	"\tAuthorize\x12\x14.pb.AuthorizeRequest\x1a\x15.pb.AuthorizeResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/authorize\x12\x8f\x01\n" +
+	"\x19CreatePersonalAccessToken\x12$.pb.CreatePersonalAccessTokenRequest\x1a%.pb.CreatePersonalAccessTokenResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/personal_access_tokens\x12\x89\x01\n" +
+	"\x18ListPersonalAccessTokens\x12#.pb.ListPersonalAccessTokensRequest\x1a$.pb.ListPersonalAccessTokensResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/personal_access_tokens\x12\x91\x01\n" +
+	"\x19RevokePersonalAccessToken\x12$.pb.RevokePersonalAccessTokenRequest\x1a%.pb.RevokePersonalAccessTokenResponse\"'\x82\xd3\xe4\x93\x02!*\x1f/v1/personal_access_tokens/{id}\x12\x82\x01\n" +
+	"\x15RegisterServiceClient\x12 .pb.RegisterServiceClientRequest\x1a!.pb.RegisterServiceClientResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/admin/service_clients\x12\xa8\x01\n" +
+	"\x19RotateServiceClientSecret\x12$.pb.RotateServiceClientSecretRequest\x1a%.pb.RotateServiceClientSecretResponse\">\x82\xd3\xe4\x93\x028:\x01*\"3/v1/admin/service_clients/{client_id}/rotate_secretB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
 
 var file_auth_service_proto_goTypes = []any{
-	(*RegisterUserRequest)(nil),     // 0: pb.RegisterUserRequest
-	(*LoginUserRequest)(nil),        // 1: pb.LoginUserRequest
-	(*UpdateUserRequest)(nil),       // 2: pb.UpdateUserRequest
-	(*VerifyEmailRequest)(nil),      // 3: pb.VerifyEmailRequest
-	(*VerifyTokenRequest)(nil),      // 4: pb.VerifyTokenRequest
-	(*VerifyRoleRequest)(nil),       // 5: pb.VerifyRoleRequest
-	(*RegisterResponsePayload)(nil), // 6: pb.RegisterResponsePayload
-	(*LoginResponsePayload)(nil),    // 7: pb.LoginResponsePayload
-	(*UpdateUserResponse)(nil),      // 8: pb.UpdateUserResponse
-	(*VerifyEmailResponse)(nil),     // 9: pb.VerifyEmailResponse
-	(*VerifyTokenResponse)(nil),     // 10: pb.VerifyTokenResponse
-	(*VerifyRoleResponse)(nil),      // 11: pb.VerifyRoleResponse
+	(*RegisterUserRequest)(nil),               // 0: pb.RegisterUserRequest
+	(*LoginUserRequest)(nil),                  // 1: pb.LoginUserRequest
+	(*RefreshTokenRequest)(nil),               // 2: pb.RefreshTokenRequest
+	(*LogoutRequest)(nil),                     // 3: pb.LogoutRequest
+	(*UpdateUserRequest)(nil),                 // 4: pb.UpdateUserRequest
+	(*VerifyEmailRequest)(nil),                // 5: pb.VerifyEmailRequest
+	(*VerifyTokenRequest)(nil),                // 6: pb.VerifyTokenRequest
+	(*VerifyRoleRequest)(nil),                 // 7: pb.VerifyRoleRequest
+	(*ListSessionsRequest)(nil),               // 8: pb.ListSessionsRequest
+	(*RevokeSessionRequest)(nil),              // 9: pb.RevokeSessionRequest
+	(*RevokeAllOtherSessionsRequest)(nil),     // 10: pb.RevokeAllOtherSessionsRequest
+	(*RotateSigningKeyRequest)(nil),           // 11: pb.RotateSigningKeyRequest
+	(*EnrollTwoFactorRequest)(nil),            // 12: pb.EnrollTwoFactorRequest
+	(*ConfirmTwoFactorRequest)(nil),           // 13: pb.ConfirmTwoFactorRequest
+	(*VerifyTwoFactorRequest)(nil),            // 14: pb.VerifyTwoFactorRequest
+	(*DisableTwoFactorRequest)(nil),           // 15: pb.DisableTwoFactorRequest
+	(*BeginPasskeyRegistrationRequest)(nil),   // 16: pb.BeginPasskeyRegistrationRequest
+	(*FinishPasskeyRegistrationRequest)(nil),  // 17: pb.FinishPasskeyRegistrationRequest
+	(*BeginPasskeyLoginRequest)(nil),          // 18: pb.BeginPasskeyLoginRequest
+	(*FinishPasskeyLoginRequest)(nil),         // 19: pb.FinishPasskeyLoginRequest
+	(*RequestPasswordResetRequest)(nil),       // 20: pb.RequestPasswordResetRequest
+	(*ResetPasswordRequest)(nil),              // 21: pb.ResetPasswordRequest
+	(*RequestMagicLinkRequest)(nil),           // 22: pb.RequestMagicLinkRequest
+	(*ConsumeMagicLinkRequest)(nil),           // 23: pb.ConsumeMagicLinkRequest
+	(*UnlockAccountRequest)(nil),              // 24: pb.UnlockAccountRequest
+	(*ListRolesRequest)(nil),                  // 25: pb.ListRolesRequest
+	(*CreateRoleRequest)(nil),                 // 26: pb.CreateRoleRequest
+	(*DeleteRoleRequest)(nil),                 // 27: pb.DeleteRoleRequest
+	(*GrantRolePermissionRequest)(nil),        // 28: pb.GrantRolePermissionRequest
+	(*RevokeRolePermissionRequest)(nil),       // 29: pb.RevokeRolePermissionRequest
+	(*AssignUserRoleRequest)(nil),             // 30: pb.AssignUserRoleRequest
+	(*RevokeUserRoleRequest)(nil),             // 31: pb.RevokeUserRoleRequest
+	(*GetUserPermissionsRequest)(nil),         // 32: pb.GetUserPermissionsRequest
+	(*AuthorizeRequest)(nil),                  // 33: pb.AuthorizeRequest
+	(*CreatePersonalAccessTokenRequest)(nil),  // 34: pb.CreatePersonalAccessTokenRequest
+	(*ListPersonalAccessTokensRequest)(nil),   // 35: pb.ListPersonalAccessTokensRequest
+	(*RevokePersonalAccessTokenRequest)(nil),  // 36: pb.RevokePersonalAccessTokenRequest
+	(*RegisterServiceClientRequest)(nil),      // 37: pb.RegisterServiceClientRequest
+	(*RotateServiceClientSecretRequest)(nil),  // 38: pb.RotateServiceClientSecretRequest
+	(*RegisterResponsePayload)(nil),           // 39: pb.RegisterResponsePayload
+	(*LoginResponsePayload)(nil),              // 40: pb.LoginResponsePayload
+	(*RefreshTokenResponse)(nil),              // 41: pb.RefreshTokenResponse
+	(*LogoutResponse)(nil),                    // 42: pb.LogoutResponse
+	(*UpdateUserResponse)(nil),                // 43: pb.UpdateUserResponse
+	(*VerifyEmailResponse)(nil),               // 44: pb.VerifyEmailResponse
+	(*VerifyTokenResponse)(nil),               // 45: pb.VerifyTokenResponse
+	(*VerifyRoleResponse)(nil),                // 46: pb.VerifyRoleResponse
+	(*ListSessionsResponse)(nil),              // 47: pb.ListSessionsResponse
+	(*RevokeSessionResponse)(nil),             // 48: pb.RevokeSessionResponse
+	(*RevokeAllOtherSessionsResponse)(nil),    // 49: pb.RevokeAllOtherSessionsResponse
+	(*RotateSigningKeyResponse)(nil),          // 50: pb.RotateSigningKeyResponse
+	(*EnrollTwoFactorResponse)(nil),           // 51: pb.EnrollTwoFactorResponse
+	(*ConfirmTwoFactorResponse)(nil),          // 52: pb.ConfirmTwoFactorResponse
+	(*VerifyTwoFactorResponse)(nil),           // 53: pb.VerifyTwoFactorResponse
+	(*DisableTwoFactorResponse)(nil),          // 54: pb.DisableTwoFactorResponse
+	(*BeginPasskeyRegistrationResponse)(nil),  // 55: pb.BeginPasskeyRegistrationResponse
+	(*FinishPasskeyRegistrationResponse)(nil), // 56: pb.FinishPasskeyRegistrationResponse
+	(*BeginPasskeyLoginResponse)(nil),         // 57: pb.BeginPasskeyLoginResponse
+	(*FinishPasskeyLoginResponse)(nil),        // 58: pb.FinishPasskeyLoginResponse
+	(*RequestPasswordResetResponse)(nil),      // 59: pb.RequestPasswordResetResponse
+	(*ResetPasswordResponse)(nil),             // 60: pb.ResetPasswordResponse
+	(*RequestMagicLinkResponse)(nil),          // 61: pb.RequestMagicLinkResponse
+	(*ConsumeMagicLinkResponse)(nil),          // 62: pb.ConsumeMagicLinkResponse
+	(*UnlockAccountResponse)(nil),             // 63: pb.UnlockAccountResponse
+	(*ListRolesResponse)(nil),                 // 64: pb.ListRolesResponse
+	(*CreateRoleResponse)(nil),                // 65: pb.CreateRoleResponse
+	(*DeleteRoleResponse)(nil),                // 66: pb.DeleteRoleResponse
+	(*GrantRolePermissionResponse)(nil),       // 67: pb.GrantRolePermissionResponse
+	(*RevokeRolePermissionResponse)(nil),      // 68: pb.RevokeRolePermissionResponse
+	(*AssignUserRoleResponse)(nil),            // 69: pb.AssignUserRoleResponse
+	(*RevokeUserRoleResponse)(nil),            // 70: pb.RevokeUserRoleResponse
+	(*GetUserPermissionsResponse)(nil),        // 71: pb.GetUserPermissionsResponse
+	(*AuthorizeResponse)(nil),                 // 72: pb.AuthorizeResponse
+	(*CreatePersonalAccessTokenResponse)(nil), // 73: pb.CreatePersonalAccessTokenResponse
+	(*ListPersonalAccessTokensResponse)(nil),  // 74: pb.ListPersonalAccessTokensResponse
+	(*RevokePersonalAccessTokenResponse)(nil), // 75: pb.RevokePersonalAccessTokenResponse
+	(*RegisterServiceClientResponse)(nil),     // 76: pb.RegisterServiceClientResponse
+	(*RotateServiceClientSecretResponse)(nil), // 77: pb.RotateServiceClientSecretResponse
 }
 var file_auth_service_proto_depIdxs = []int32{
 	0,  // 0: pb.AuthService.RegisterUser:input_type -> pb.RegisterUserRequest
 	1,  // 1: pb.AuthService.LoginUser:input_type -> pb.LoginUserRequest
-	2,  // 2: pb.AuthService.UpdateUser:input_type -> pb.UpdateUserRequest
-	3,  // 3: pb.AuthService.VerifyEmail:input_type -> pb.VerifyEmailRequest
-	4,  // 4: pb.AuthService.VerifyToken:input_type -> pb.VerifyTokenRequest
-	5,  // 5: pb.AuthService.VerifyRole:input_type -> pb.VerifyRoleRequest
-	6,  // 6: pb.AuthService.RegisterUser:output_type -> pb.RegisterResponsePayload
-	7,  // 7: pb.AuthService.LoginUser:output_type -> pb.LoginResponsePayload
-	8,  // 8: pb.AuthService.UpdateUser:output_type -> pb.UpdateUserResponse
-	9,  // 9: pb.AuthService.VerifyEmail:output_type -> pb.VerifyEmailResponse
-	10, // 10: pb.AuthService.VerifyToken:output_type -> pb.VerifyTokenResponse
-	11, // 11: pb.AuthService.VerifyRole:output_type -> pb.VerifyRoleResponse
-	6,  // [6:12] is the sub-list for method output_type
-	0,  // [0:6] is the sub-list for method input_type
+	2,  // 2: pb.AuthService.RefreshToken:input_type -> pb.RefreshTokenRequest
+	3,  // 3: pb.AuthService.Logout:input_type -> pb.LogoutRequest
+	4,  // 4: pb.AuthService.UpdateUser:input_type -> pb.UpdateUserRequest
+	5,  // 5: pb.AuthService.VerifyEmail:input_type -> pb.VerifyEmailRequest
+	6,  // 6: pb.AuthService.VerifyToken:input_type -> pb.VerifyTokenRequest
+	7,  // 7: pb.AuthService.VerifyRole:input_type -> pb.VerifyRoleRequest
+	8,  // 8: pb.AuthService.ListSessions:input_type -> pb.ListSessionsRequest
+	9,  // 9: pb.AuthService.RevokeSession:input_type -> pb.RevokeSessionRequest
+	10, // 10: pb.AuthService.RevokeAllOtherSessions:input_type -> pb.RevokeAllOtherSessionsRequest
+	11, // 11: pb.AuthService.RotateSigningKey:input_type -> pb.RotateSigningKeyRequest
+	12, // 12: pb.AuthService.EnrollTwoFactor:input_type -> pb.EnrollTwoFactorRequest
+	13, // 13: pb.AuthService.ConfirmTwoFactor:input_type -> pb.ConfirmTwoFactorRequest
+	14, // 14: pb.AuthService.VerifyTwoFactor:input_type -> pb.VerifyTwoFactorRequest
+	15, // 15: pb.AuthService.DisableTwoFactor:input_type -> pb.DisableTwoFactorRequest
+	16, // 16: pb.AuthService.BeginPasskeyRegistration:input_type -> pb.BeginPasskeyRegistrationRequest
+	17, // 17: pb.AuthService.FinishPasskeyRegistration:input_type -> pb.FinishPasskeyRegistrationRequest
+	18, // 18: pb.AuthService.BeginPasskeyLogin:input_type -> pb.BeginPasskeyLoginRequest
+	19, // 19: pb.AuthService.FinishPasskeyLogin:input_type -> pb.FinishPasskeyLoginRequest
+	20, // 20: pb.AuthService.RequestPasswordReset:input_type -> pb.RequestPasswordResetRequest
+	21, // 21: pb.AuthService.ResetPassword:input_type -> pb.ResetPasswordRequest
+	22, // 22: pb.AuthService.RequestMagicLink:input_type -> pb.RequestMagicLinkRequest
+	23, // 23: pb.AuthService.ConsumeMagicLink:input_type -> pb.ConsumeMagicLinkRequest
+	24, // 24: pb.AuthService.UnlockAccount:input_type -> pb.UnlockAccountRequest
+	25, // 25: pb.AuthService.ListRoles:input_type -> pb.ListRolesRequest
+	26, // 26: pb.AuthService.CreateRole:input_type -> pb.CreateRoleRequest
+	27, // 27: pb.AuthService.DeleteRole:input_type -> pb.DeleteRoleRequest
+	28, // 28: pb.AuthService.GrantRolePermission:input_type -> pb.GrantRolePermissionRequest
+	29, // 29: pb.AuthService.RevokeRolePermission:input_type -> pb.RevokeRolePermissionRequest
+	30, // 30: pb.AuthService.AssignUserRole:input_type -> pb.AssignUserRoleRequest
+	31, // 31: pb.AuthService.RevokeUserRole:input_type -> pb.RevokeUserRoleRequest
+	32, // 32: pb.AuthService.GetUserPermissions:input_type -> pb.GetUserPermissionsRequest
+	33, // 33: pb.AuthService.Authorize:input_type -> pb.AuthorizeRequest
+	34, // 34: pb.AuthService.CreatePersonalAccessToken:input_type -> pb.CreatePersonalAccessTokenRequest
+	35, // 35: pb.AuthService.ListPersonalAccessTokens:input_type -> pb.ListPersonalAccessTokensRequest
+	36, // 36: pb.AuthService.RevokePersonalAccessToken:input_type -> pb.RevokePersonalAccessTokenRequest
+	37, // 37: pb.AuthService.RegisterServiceClient:input_type -> pb.RegisterServiceClientRequest
+	38, // 38: pb.AuthService.RotateServiceClientSecret:input_type -> pb.RotateServiceClientSecretRequest
+	39, // 39: pb.AuthService.RegisterUser:output_type -> pb.RegisterResponsePayload
+	40, // 40: pb.AuthService.LoginUser:output_type -> pb.LoginResponsePayload
+	41, // 41: pb.AuthService.RefreshToken:output_type -> pb.RefreshTokenResponse
+	42, // 42: pb.AuthService.Logout:output_type -> pb.LogoutResponse
+	43, // 43: pb.AuthService.UpdateUser:output_type -> pb.UpdateUserResponse
+	44, // 44: pb.AuthService.VerifyEmail:output_type -> pb.VerifyEmailResponse
+	45, // 45: pb.AuthService.VerifyToken:output_type -> pb.VerifyTokenResponse
+	46, // 46: pb.AuthService.VerifyRole:output_type -> pb.VerifyRoleResponse
+	47, // 47: pb.AuthService.ListSessions:output_type -> pb.ListSessionsResponse
+	48, // 48: pb.AuthService.RevokeSession:output_type -> pb.RevokeSessionResponse
+	49, // 49: pb.AuthService.RevokeAllOtherSessions:output_type -> pb.RevokeAllOtherSessionsResponse
+	50, // 50: pb.AuthService.RotateSigningKey:output_type -> pb.RotateSigningKeyResponse
+	51, // 51: pb.AuthService.EnrollTwoFactor:output_type -> pb.EnrollTwoFactorResponse
+	52, // 52: pb.AuthService.ConfirmTwoFactor:output_type -> pb.ConfirmTwoFactorResponse
+	53, // 53: pb.AuthService.VerifyTwoFactor:output_type -> pb.VerifyTwoFactorResponse
+	54, // 54: pb.AuthService.DisableTwoFactor:output_type -> pb.DisableTwoFactorResponse
+	55, // 55: pb.AuthService.BeginPasskeyRegistration:output_type -> pb.BeginPasskeyRegistrationResponse
+	56, // 56: pb.AuthService.FinishPasskeyRegistration:output_type -> pb.FinishPasskeyRegistrationResponse
+	57, // 57: pb.AuthService.BeginPasskeyLogin:output_type -> pb.BeginPasskeyLoginResponse
+	58, // 58: pb.AuthService.FinishPasskeyLogin:output_type -> pb.FinishPasskeyLoginResponse
+	59, // 59: pb.AuthService.RequestPasswordReset:output_type -> pb.RequestPasswordResetResponse
+	60, // 60: pb.AuthService.ResetPassword:output_type -> pb.ResetPasswordResponse
+	61, // 61: pb.AuthService.RequestMagicLink:output_type -> pb.RequestMagicLinkResponse
+	62, // 62: pb.AuthService.ConsumeMagicLink:output_type -> pb.ConsumeMagicLinkResponse
+	63, // 63: pb.AuthService.UnlockAccount:output_type -> pb.UnlockAccountResponse
+	64, // 64: pb.AuthService.ListRoles:output_type -> pb.ListRolesResponse
+	65, // 65: pb.AuthService.CreateRole:output_type -> pb.CreateRoleResponse
+	66, // 66: pb.AuthService.DeleteRole:output_type -> pb.DeleteRoleResponse
+	67, // 67: pb.AuthService.GrantRolePermission:output_type -> pb.GrantRolePermissionResponse
+	68, // 68: pb.AuthService.RevokeRolePermission:output_type -> pb.RevokeRolePermissionResponse
+	69, // 69: pb.AuthService.AssignUserRole:output_type -> pb.AssignUserRoleResponse
+	70, // 70: pb.AuthService.RevokeUserRole:output_type -> pb.RevokeUserRoleResponse
+	71, // 71: pb.AuthService.GetUserPermissions:output_type -> pb.GetUserPermissionsResponse
+	72, // 72: pb.AuthService.Authorize:output_type -> pb.AuthorizeResponse
+	73, // 73: pb.AuthService.CreatePersonalAccessToken:output_type -> pb.CreatePersonalAccessTokenResponse
+	74, // 74: pb.AuthService.ListPersonalAccessTokens:output_type -> pb.ListPersonalAccessTokensResponse
+	75, // 75: pb.AuthService.RevokePersonalAccessToken:output_type -> pb.RevokePersonalAccessTokenResponse
+	76, // 76: pb.AuthService.RegisterServiceClient:output_type -> pb.RegisterServiceClientResponse
+	77, // 77: pb.AuthService.RotateServiceClientSecret:output_type -> pb.RotateServiceClientSecretResponse
+	39, // [39:78] is the sub-list for method output_type
+	0,  // [0:39] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -81,6 +249,19 @@ func file_auth_service_proto_init() {
 	file_rpc_verify_email_proto_init()
 	file_rpc_verify_token_proto_init()
 	file_rpc_verify_role_proto_init()
+	file_rpc_refresh_token_proto_init()
+	file_rpc_session_proto_init()
+	file_rpc_logout_proto_init()
+	file_rpc_rotate_signing_key_proto_init()
+	file_rpc_two_factor_proto_init()
+	file_rpc_passkey_proto_init()
+	file_rpc_password_reset_proto_init()
+	file_rpc_magic_link_proto_init()
+	file_rpc_unlock_account_proto_init()
+	file_rpc_rbac_proto_init()
+	file_rpc_authorize_proto_init()
+	file_rpc_personal_access_token_proto_init()
+	file_rpc_service_client_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
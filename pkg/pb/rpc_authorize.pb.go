@@ -0,0 +1,291 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: rpc_authorize.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AuthorizeResource struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "user" - в политику попадут поля юзверя и его профиль, id - id юзверя
+	Type          string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorizeResource) Reset() {
+	*x = AuthorizeResource{}
+	mi := &file_rpc_authorize_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeResource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeResource) ProtoMessage() {}
+
+func (x *AuthorizeResource) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_authorize_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeResource.ProtoReflect.Descriptor instead.
+func (*AuthorizeResource) Descriptor() ([]byte, []int) {
+	return file_rpc_authorize_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuthorizeResource) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *AuthorizeResource) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type AuthorizeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// чей доступ проверяем; 0 - владельца токена, другой - нужно право policies.evaluate
+	SubjectId int64 `protobuf:"varint,1,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	// "profile.edit", "wallet.withdraw"
+	Action   string             `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Resource *AuthorizeResource `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	// произвольные данные запроса, в политиках доступны как attributes
+	Attributes    *structpb.Struct `protobuf:"bytes,4,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorizeRequest) Reset() {
+	*x = AuthorizeRequest{}
+	mi := &file_rpc_authorize_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeRequest) ProtoMessage() {}
+
+func (x *AuthorizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_authorize_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeRequest.ProtoReflect.Descriptor instead.
+func (*AuthorizeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_authorize_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AuthorizeRequest) GetSubjectId() int64 {
+	if x != nil {
+		return x.SubjectId
+	}
+	return 0
+}
+
+func (x *AuthorizeRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuthorizeRequest) GetResource() *AuthorizeResource {
+	if x != nil {
+		return x.Resource
+	}
+	return nil
+}
+
+func (x *AuthorizeRequest) GetAttributes() *structpb.Struct {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type AuthorizeResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Allowed bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	// политика, которая решила; пусто - ни одна не подошла и доступ запрещен по умолчанию
+	Policy        string `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	Effect        string `protobuf:"bytes,3,opt,name=effect,proto3" json:"effect,omitempty"`
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorizeResponse) Reset() {
+	*x = AuthorizeResponse{}
+	mi := &file_rpc_authorize_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeResponse) ProtoMessage() {}
+
+func (x *AuthorizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_authorize_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeResponse.ProtoReflect.Descriptor instead.
+func (*AuthorizeResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_authorize_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AuthorizeResponse) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *AuthorizeResponse) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *AuthorizeResponse) GetEffect() string {
+	if x != nil {
+		return x.Effect
+	}
+	return ""
+}
+
+func (x *AuthorizeResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+var File_rpc_authorize_proto protoreflect.FileDescriptor
+
+const file_rpc_authorize_proto_rawDesc = "" +
+	"\n" +
+	"\x13rpc_authorize.proto\x12\x02pb\x1a\x1cgoogle/protobuf/struct.proto\"7\n" +
+	"\x11AuthorizeResource\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"\xb5\x01\n" +
+	"\x10AuthorizeRequest\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x01 \x01(\x03R\tsubjectId\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x121\n" +
+	"\bresource\x18\x03 \x01(\v2\x15.pb.AuthorizeResourceR\bresource\x127\n" +
+	"\n" +
+	"attributes\x18\x04 \x01(\v2\x17.google.protobuf.StructR\n" +
+	"attributes\"u\n" +
+	"\x11AuthorizeResponse\x12\x18\n" +
+	"\aallowed\x18\x01 \x01(\bR\aallowed\x12\x16\n" +
+	"\x06policy\x18\x02 \x01(\tR\x06policy\x12\x16\n" +
+	"\x06effect\x18\x03 \x01(\tR\x06effect\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reasonB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
+
+var (
+	file_rpc_authorize_proto_rawDescOnce sync.Once
+	file_rpc_authorize_proto_rawDescData []byte
+)
+
+func file_rpc_authorize_proto_rawDescGZIP() []byte {
+	file_rpc_authorize_proto_rawDescOnce.Do(func() {
+		file_rpc_authorize_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_authorize_proto_rawDesc), len(file_rpc_authorize_proto_rawDesc)))
+	})
+	return file_rpc_authorize_proto_rawDescData
+}
+
+var file_rpc_authorize_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_rpc_authorize_proto_goTypes = []any{
+	(*AuthorizeResource)(nil), // 0: pb.AuthorizeResource
+	(*AuthorizeRequest)(nil),  // 1: pb.AuthorizeRequest
+	(*AuthorizeResponse)(nil), // 2: pb.AuthorizeResponse
+	(*structpb.Struct)(nil),   // 3: google.protobuf.Struct
+}
+var file_rpc_authorize_proto_depIdxs = []int32{
+	0, // 0: pb.AuthorizeRequest.resource:type_name -> pb.AuthorizeResource
+	3, // 1: pb.AuthorizeRequest.attributes:type_name -> google.protobuf.Struct
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_authorize_proto_init() }
+func file_rpc_authorize_proto_init() {
+	if File_rpc_authorize_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_authorize_proto_rawDesc), len(file_rpc_authorize_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_authorize_proto_goTypes,
+		DependencyIndexes: file_rpc_authorize_proto_depIdxs,
+		MessageInfos:      file_rpc_authorize_proto_msgTypes,
+	}.Build()
+	File_rpc_authorize_proto = out.File
+	file_rpc_authorize_proto_goTypes = nil
+	file_rpc_authorize_proto_depIdxs = nil
+}
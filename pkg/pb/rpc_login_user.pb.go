@@ -22,10 +22,69 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type LoginErrorCode int32
+
+const (
+	LoginErrorCode_LOGIN_ERROR_CODE_UNSPECIFIED        LoginErrorCode = 0
+	LoginErrorCode_LOGIN_ERROR_CODE_WRONG_CREDENTIALS  LoginErrorCode = 1
+	LoginErrorCode_LOGIN_ERROR_CODE_EMAIL_NOT_VERIFIED LoginErrorCode = 2
+	// прогрессивная задержка после неудачных попыток
+	LoginErrorCode_LOGIN_ERROR_CODE_TOO_MANY_ATTEMPTS LoginErrorCode = 3
+	// аккаунт или IP временно заблокированы
+	LoginErrorCode_LOGIN_ERROR_CODE_ACCOUNT_LOCKED LoginErrorCode = 4
+)
+
+// Enum value maps for LoginErrorCode.
+var (
+	LoginErrorCode_name = map[int32]string{
+		0: "LOGIN_ERROR_CODE_UNSPECIFIED",
+		1: "LOGIN_ERROR_CODE_WRONG_CREDENTIALS",
+		2: "LOGIN_ERROR_CODE_EMAIL_NOT_VERIFIED",
+		3: "LOGIN_ERROR_CODE_TOO_MANY_ATTEMPTS",
+		4: "LOGIN_ERROR_CODE_ACCOUNT_LOCKED",
+	}
+	LoginErrorCode_value = map[string]int32{
+		"LOGIN_ERROR_CODE_UNSPECIFIED":        0,
+		"LOGIN_ERROR_CODE_WRONG_CREDENTIALS":  1,
+		"LOGIN_ERROR_CODE_EMAIL_NOT_VERIFIED": 2,
+		"LOGIN_ERROR_CODE_TOO_MANY_ATTEMPTS":  3,
+		"LOGIN_ERROR_CODE_ACCOUNT_LOCKED":     4,
+	}
+)
+
+func (x LoginErrorCode) Enum() *LoginErrorCode {
+	p := new(LoginErrorCode)
+	*p = x
+	return p
+}
+
+func (x LoginErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LoginErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_rpc_login_user_proto_enumTypes[0].Descriptor()
+}
+
+func (LoginErrorCode) Type() protoreflect.EnumType {
+	return &file_rpc_login_user_proto_enumTypes[0]
+}
+
+func (x LoginErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LoginErrorCode.Descriptor instead.
+func (LoginErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_rpc_login_user_proto_rawDescGZIP(), []int{0}
+}
+
 type LoginUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Email    string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// обязателен: один и тот же email может быть в разных приложениях
+	AppId         int64 `protobuf:"varint,3,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -74,13 +133,28 @@ func (x *LoginUserRequest) GetPassword() string {
 	return ""
 }
 
+func (x *LoginUserRequest) GetAppId() int64 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
 type LoginResponsePayload struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         bool                   `protobuf:"varint,1,opt,name=error,proto3" json:"error,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Token         *Token                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Error        bool                   `protobuf:"varint,1,opt,name=error,proto3" json:"error,omitempty"`
+	Message      string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Token        *Token                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken *Token                 `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// включена 2FA: токенов нет, их выдаст VerifyTwoFactor по challenge_token и коду
+	TwoFactorRequired bool   `protobuf:"varint,5,opt,name=two_factor_required,json=twoFactorRequired,proto3" json:"two_factor_required,omitempty"`
+	ChallengeToken    string `protobuf:"bytes,6,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	// машиночитаемая причина отказа, message - для людей
+	ErrorCode LoginErrorCode `protobuf:"varint,7,opt,name=error_code,json=errorCode,proto3,enum=pb.LoginErrorCode" json:"error_code,omitempty"`
+	// для TOO_MANY_ATTEMPTS и ACCOUNT_LOCKED: через сколько секунд можно пробовать снова
+	RetryAfterSeconds int64 `protobuf:"varint,8,opt,name=retry_after_seconds,json=retryAfterSeconds,proto3" json:"retry_after_seconds,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *LoginResponsePayload) Reset() {
@@ -134,6 +208,41 @@ func (x *LoginResponsePayload) GetToken() *Token {
 	return nil
 }
 
+func (x *LoginResponsePayload) GetRefreshToken() *Token {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return nil
+}
+
+func (x *LoginResponsePayload) GetTwoFactorRequired() bool {
+	if x != nil {
+		return x.TwoFactorRequired
+	}
+	return false
+}
+
+func (x *LoginResponsePayload) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *LoginResponsePayload) GetErrorCode() LoginErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return LoginErrorCode_LOGIN_ERROR_CODE_UNSPECIFIED
+}
+
+func (x *LoginResponsePayload) GetRetryAfterSeconds() int64 {
+	if x != nil {
+		return x.RetryAfterSeconds
+	}
+	return 0
+}
+
 type Token struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Plaintext     string                 `protobuf:"bytes,1,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
@@ -142,6 +251,7 @@ type Token struct {
 	Expiry        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expiry,proto3" json:"expiry,omitempty"`
 	Scope         string                 `protobuf:"bytes,5,opt,name=scope,proto3" json:"scope,omitempty"`
 	Role          string                 `protobuf:"bytes,6,opt,name=role,proto3" json:"role,omitempty"`
+	AppId         int64                  `protobuf:"varint,7,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -218,25 +328,46 @@ func (x *Token) GetRole() string {
 	return ""
 }
 
+func (x *Token) GetAppId() int64 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
 var File_rpc_login_user_proto protoreflect.FileDescriptor
 
 const file_rpc_login_user_proto_rawDesc = "" +
 	"\n" +
-	"\x14rpc_login_user.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"D\n" +
+	"\x14rpc_login_user.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"[\n" +
 	"\x10LoginUserRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"g\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x15\n" +
+	"\x06app_id\x18\x03 \x01(\x03R\x05appId\"\xd3\x02\n" +
 	"\x14LoginResponsePayload\x12\x14\n" +
 	"\x05error\x18\x01 \x01(\bR\x05error\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
-	"\x05token\x18\x03 \x01(\v2\t.pb.TokenR\x05token\"\xaf\x01\n" +
+	"\x05token\x18\x03 \x01(\v2\t.pb.TokenR\x05token\x12.\n" +
+	"\rrefresh_token\x18\x04 \x01(\v2\t.pb.TokenR\frefreshToken\x12.\n" +
+	"\x13two_factor_required\x18\x05 \x01(\bR\x11twoFactorRequired\x12'\n" +
+	"\x0fchallenge_token\x18\x06 \x01(\tR\x0echallengeToken\x121\n" +
+	"\n" +
+	"error_code\x18\a \x01(\x0e2\x12.pb.LoginErrorCodeR\terrorCode\x12.\n" +
+	"\x13retry_after_seconds\x18\b \x01(\x03R\x11retryAfterSeconds\"\xc6\x01\n" +
 	"\x05Token\x12\x1c\n" +
 	"\tplaintext\x18\x01 \x01(\tR\tplaintext\x12\x16\n" +
 	"\x06userid\x18\x02 \x01(\x03R\x06userid\x12\x12\n" +
 	"\x04hash\x18\x03 \x01(\fR\x04hash\x122\n" +
 	"\x06expiry\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06expiry\x12\x14\n" +
 	"\x05scope\x18\x05 \x01(\tR\x05scope\x12\x12\n" +
-	"\x04role\x18\x06 \x01(\tR\x04roleB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
+	"\x04role\x18\x06 \x01(\tR\x04role\x12\x15\n" +
+	"\x06app_id\x18\a \x01(\x03R\x05appId*\xd0\x01\n" +
+	"\x0eLoginErrorCode\x12 \n" +
+	"\x1cLOGIN_ERROR_CODE_UNSPECIFIED\x10\x00\x12&\n" +
+	"\"LOGIN_ERROR_CODE_WRONG_CREDENTIALS\x10\x01\x12'\n" +
+	"#LOGIN_ERROR_CODE_EMAIL_NOT_VERIFIED\x10\x02\x12&\n" +
+	"\"LOGIN_ERROR_CODE_TOO_MANY_ATTEMPTS\x10\x03\x12#\n" +
+	"\x1fLOGIN_ERROR_CODE_ACCOUNT_LOCKED\x10\x04B*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
 
 var (
 	file_rpc_login_user_proto_rawDescOnce sync.Once
@@ -250,21 +381,25 @@ func file_rpc_login_user_proto_rawDescGZIP() []byte {
 	return file_rpc_login_user_proto_rawDescData
 }
 
+var file_rpc_login_user_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
 var file_rpc_login_user_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_rpc_login_user_proto_goTypes = []any{
-	(*LoginUserRequest)(nil),      // 0: pb.LoginUserRequest
-	(*LoginResponsePayload)(nil),  // 1: pb.LoginResponsePayload
-	(*Token)(nil),                 // 2: pb.Token
-	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(LoginErrorCode)(0),           // 0: pb.LoginErrorCode
+	(*LoginUserRequest)(nil),      // 1: pb.LoginUserRequest
+	(*LoginResponsePayload)(nil),  // 2: pb.LoginResponsePayload
+	(*Token)(nil),                 // 3: pb.Token
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
 }
 var file_rpc_login_user_proto_depIdxs = []int32{
-	2, // 0: pb.LoginResponsePayload.token:type_name -> pb.Token
-	3, // 1: pb.Token.expiry:type_name -> google.protobuf.Timestamp
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	3, // 0: pb.LoginResponsePayload.token:type_name -> pb.Token
+	3, // 1: pb.LoginResponsePayload.refresh_token:type_name -> pb.Token
+	0, // 2: pb.LoginResponsePayload.error_code:type_name -> pb.LoginErrorCode
+	4, // 3: pb.Token.expiry:type_name -> google.protobuf.Timestamp
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_rpc_login_user_proto_init() }
@@ -277,13 +412,14 @@ func file_rpc_login_user_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_login_user_proto_rawDesc), len(file_rpc_login_user_proto_rawDesc)),
-			NumEnums:      0,
+			NumEnums:      1,
 			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_rpc_login_user_proto_goTypes,
 		DependencyIndexes: file_rpc_login_user_proto_depIdxs,
+		EnumInfos:         file_rpc_login_user_proto_enumTypes,
 		MessageInfos:      file_rpc_login_user_proto_msgTypes,
 	}.Build()
 	File_rpc_login_user_proto = out.File
@@ -0,0 +1,306 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: rpc_magic_link.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RequestMagicLinkRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Email string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// 0 - приложение по умолчанию
+	AppId         int64 `protobuf:"varint,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestMagicLinkRequest) Reset() {
+	*x = RequestMagicLinkRequest{}
+	mi := &file_rpc_magic_link_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestMagicLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestMagicLinkRequest) ProtoMessage() {}
+
+func (x *RequestMagicLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_magic_link_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestMagicLinkRequest.ProtoReflect.Descriptor instead.
+func (*RequestMagicLinkRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_magic_link_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RequestMagicLinkRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *RequestMagicLinkRequest) GetAppId() int64 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+// ответ одинаковый, есть такой email или нет
+type RequestMagicLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestMagicLinkResponse) Reset() {
+	*x = RequestMagicLinkResponse{}
+	mi := &file_rpc_magic_link_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestMagicLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestMagicLinkResponse) ProtoMessage() {}
+
+func (x *RequestMagicLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_magic_link_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestMagicLinkResponse.ProtoReflect.Descriptor instead.
+func (*RequestMagicLinkResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_magic_link_proto_rawDescGZIP(), []int{1}
+}
+
+type ConsumeMagicLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LinkId        int64                  `protobuf:"varint,1,opt,name=link_id,json=linkId,proto3" json:"link_id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsumeMagicLinkRequest) Reset() {
+	*x = ConsumeMagicLinkRequest{}
+	mi := &file_rpc_magic_link_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsumeMagicLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsumeMagicLinkRequest) ProtoMessage() {}
+
+func (x *ConsumeMagicLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_magic_link_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsumeMagicLinkRequest.ProtoReflect.Descriptor instead.
+func (*ConsumeMagicLinkRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_magic_link_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConsumeMagicLinkRequest) GetLinkId() int64 {
+	if x != nil {
+		return x.LinkId
+	}
+	return 0
+}
+
+func (x *ConsumeMagicLinkRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// как и LoginUser: либо токены, либо challenge для VerifyTwoFactor
+type ConsumeMagicLinkResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken       *Token                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken      *Token                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	TwoFactorRequired bool                   `protobuf:"varint,3,opt,name=two_factor_required,json=twoFactorRequired,proto3" json:"two_factor_required,omitempty"`
+	ChallengeToken    string                 `protobuf:"bytes,4,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ConsumeMagicLinkResponse) Reset() {
+	*x = ConsumeMagicLinkResponse{}
+	mi := &file_rpc_magic_link_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsumeMagicLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsumeMagicLinkResponse) ProtoMessage() {}
+
+func (x *ConsumeMagicLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_magic_link_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsumeMagicLinkResponse.ProtoReflect.Descriptor instead.
+func (*ConsumeMagicLinkResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_magic_link_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ConsumeMagicLinkResponse) GetAccessToken() *Token {
+	if x != nil {
+		return x.AccessToken
+	}
+	return nil
+}
+
+func (x *ConsumeMagicLinkResponse) GetRefreshToken() *Token {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return nil
+}
+
+func (x *ConsumeMagicLinkResponse) GetTwoFactorRequired() bool {
+	if x != nil {
+		return x.TwoFactorRequired
+	}
+	return false
+}
+
+func (x *ConsumeMagicLinkResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+var File_rpc_magic_link_proto protoreflect.FileDescriptor
+
+const file_rpc_magic_link_proto_rawDesc = "" +
+	"\n" +
+	"\x14rpc_magic_link.proto\x12\x02pb\x1a\x14rpc_login_user.proto\"F\n" +
+	"\x17RequestMagicLinkRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x15\n" +
+	"\x06app_id\x18\x02 \x01(\x03R\x05appId\"\x1a\n" +
+	"\x18RequestMagicLinkResponse\"F\n" +
+	"\x17ConsumeMagicLinkRequest\x12\x17\n" +
+	"\alink_id\x18\x01 \x01(\x03R\x06linkId\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"\xd1\x01\n" +
+	"\x18ConsumeMagicLinkResponse\x12,\n" +
+	"\faccess_token\x18\x01 \x01(\v2\t.pb.TokenR\vaccessToken\x12.\n" +
+	"\rrefresh_token\x18\x02 \x01(\v2\t.pb.TokenR\frefreshToken\x12.\n" +
+	"\x13two_factor_required\x18\x03 \x01(\bR\x11twoFactorRequired\x12'\n" +
+	"\x0fchallenge_token\x18\x04 \x01(\tR\x0echallengeTokenB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
+
+var (
+	file_rpc_magic_link_proto_rawDescOnce sync.Once
+	file_rpc_magic_link_proto_rawDescData []byte
+)
+
+func file_rpc_magic_link_proto_rawDescGZIP() []byte {
+	file_rpc_magic_link_proto_rawDescOnce.Do(func() {
+		file_rpc_magic_link_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_magic_link_proto_rawDesc), len(file_rpc_magic_link_proto_rawDesc)))
+	})
+	return file_rpc_magic_link_proto_rawDescData
+}
+
+var file_rpc_magic_link_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_rpc_magic_link_proto_goTypes = []any{
+	(*RequestMagicLinkRequest)(nil),  // 0: pb.RequestMagicLinkRequest
+	(*RequestMagicLinkResponse)(nil), // 1: pb.RequestMagicLinkResponse
+	(*ConsumeMagicLinkRequest)(nil),  // 2: pb.ConsumeMagicLinkRequest
+	(*ConsumeMagicLinkResponse)(nil), // 3: pb.ConsumeMagicLinkResponse
+	(*Token)(nil),                    // 4: pb.Token
+}
+var file_rpc_magic_link_proto_depIdxs = []int32{
+	4, // 0: pb.ConsumeMagicLinkResponse.access_token:type_name -> pb.Token
+	4, // 1: pb.ConsumeMagicLinkResponse.refresh_token:type_name -> pb.Token
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_magic_link_proto_init() }
+func file_rpc_magic_link_proto_init() {
+	if File_rpc_magic_link_proto != nil {
+		return
+	}
+	file_rpc_login_user_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_magic_link_proto_rawDesc), len(file_rpc_magic_link_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_magic_link_proto_goTypes,
+		DependencyIndexes: file_rpc_magic_link_proto_depIdxs,
+		MessageInfos:      file_rpc_magic_link_proto_msgTypes,
+	}.Build()
+	File_rpc_magic_link_proto = out.File
+	file_rpc_magic_link_proto_goTypes = nil
+	file_rpc_magic_link_proto_depIdxs = nil
+}
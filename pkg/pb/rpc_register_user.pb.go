@@ -26,6 +26,7 @@ type RegisterUserRequest struct {
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
 	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	AppId         int64                  `protobuf:"varint,4,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -81,6 +82,13 @@ func (x *RegisterUserRequest) GetPassword() string {
 	return ""
 }
 
+func (x *RegisterUserRequest) GetAppId() int64 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
 type RegisterResponsePayload struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Error         bool                   `protobuf:"varint,1,opt,name=error,proto3" json:"error,omitempty"`
@@ -137,11 +145,12 @@ var File_rpc_register_user_proto protoreflect.FileDescriptor
 
 const file_rpc_register_user_proto_rawDesc = "" +
 	"\n" +
-	"\x17rpc_register_user.proto\x12\x02pb\"[\n" +
+	"\x17rpc_register_user.proto\x12\x02pb\"r\n" +
 	"\x13RegisterUserRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x03 \x01(\tR\bpassword\"I\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x15\n" +
+	"\x06app_id\x18\x04 \x01(\x03R\x05appId\"I\n" +
 	"\x17RegisterResponsePayload\x12\x14\n" +
 	"\x05error\x18\x01 \x01(\bR\x05error\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessageB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
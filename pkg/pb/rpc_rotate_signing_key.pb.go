@@ -0,0 +1,184 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: rpc_rotate_signing_key.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RotateSigningKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateSigningKeyRequest) Reset() {
+	*x = RotateSigningKeyRequest{}
+	mi := &file_rpc_rotate_signing_key_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateSigningKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSigningKeyRequest) ProtoMessage() {}
+
+func (x *RotateSigningKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rotate_signing_key_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSigningKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateSigningKeyRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rotate_signing_key_proto_rawDescGZIP(), []int{0}
+}
+
+type RotateSigningKeyResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Kid       string                 `protobuf:"bytes,1,opt,name=kid,proto3" json:"kid,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// до этого момента ключ только публикуется в JWKS, подписывать им начнут позже
+	ActivatesAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=activates_at,json=activatesAt,proto3" json:"activates_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateSigningKeyResponse) Reset() {
+	*x = RotateSigningKeyResponse{}
+	mi := &file_rpc_rotate_signing_key_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateSigningKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSigningKeyResponse) ProtoMessage() {}
+
+func (x *RotateSigningKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rotate_signing_key_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSigningKeyResponse.ProtoReflect.Descriptor instead.
+func (*RotateSigningKeyResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rotate_signing_key_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RotateSigningKeyResponse) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+func (x *RotateSigningKeyResponse) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *RotateSigningKeyResponse) GetActivatesAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActivatesAt
+	}
+	return nil
+}
+
+var File_rpc_rotate_signing_key_proto protoreflect.FileDescriptor
+
+const file_rpc_rotate_signing_key_proto_rawDesc = "" +
+	"\n" +
+	"\x1crpc_rotate_signing_key.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\x19\n" +
+	"\x17RotateSigningKeyRequest\"\xa6\x01\n" +
+	"\x18RotateSigningKeyResponse\x12\x10\n" +
+	"\x03kid\x18\x01 \x01(\tR\x03kid\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\factivates_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vactivatesAtB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
+
+var (
+	file_rpc_rotate_signing_key_proto_rawDescOnce sync.Once
+	file_rpc_rotate_signing_key_proto_rawDescData []byte
+)
+
+func file_rpc_rotate_signing_key_proto_rawDescGZIP() []byte {
+	file_rpc_rotate_signing_key_proto_rawDescOnce.Do(func() {
+		file_rpc_rotate_signing_key_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_rotate_signing_key_proto_rawDesc), len(file_rpc_rotate_signing_key_proto_rawDesc)))
+	})
+	return file_rpc_rotate_signing_key_proto_rawDescData
+}
+
+var file_rpc_rotate_signing_key_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_rotate_signing_key_proto_goTypes = []any{
+	(*RotateSigningKeyRequest)(nil),  // 0: pb.RotateSigningKeyRequest
+	(*RotateSigningKeyResponse)(nil), // 1: pb.RotateSigningKeyResponse
+	(*timestamppb.Timestamp)(nil),    // 2: google.protobuf.Timestamp
+}
+var file_rpc_rotate_signing_key_proto_depIdxs = []int32{
+	2, // 0: pb.RotateSigningKeyResponse.created_at:type_name -> google.protobuf.Timestamp
+	2, // 1: pb.RotateSigningKeyResponse.activates_at:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_rotate_signing_key_proto_init() }
+func file_rpc_rotate_signing_key_proto_init() {
+	if File_rpc_rotate_signing_key_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_rotate_signing_key_proto_rawDesc), len(file_rpc_rotate_signing_key_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_rotate_signing_key_proto_goTypes,
+		DependencyIndexes: file_rpc_rotate_signing_key_proto_depIdxs,
+		MessageInfos:      file_rpc_rotate_signing_key_proto_msgTypes,
+	}.Build()
+	File_rpc_rotate_signing_key_proto = out.File
+	file_rpc_rotate_signing_key_proto_goTypes = nil
+	file_rpc_rotate_signing_key_proto_depIdxs = nil
+}
@@ -0,0 +1,382 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: rpc_service_client.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// машинный клиент: получает токены на /oauth/token по grant_type=client_credentials
+type ServiceClient struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ClientId        string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes          []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	SecretRotatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=secret_rotated_at,json=secretRotatedAt,proto3" json:"secret_rotated_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ServiceClient) Reset() {
+	*x = ServiceClient{}
+	mi := &file_rpc_service_client_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceClient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceClient) ProtoMessage() {}
+
+func (x *ServiceClient) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_client_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceClient.ProtoReflect.Descriptor instead.
+func (*ServiceClient) Descriptor() ([]byte, []int) {
+	return file_rpc_service_client_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ServiceClient) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ServiceClient) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceClient) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ServiceClient) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ServiceClient) GetSecretRotatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SecretRotatedAt
+	}
+	return nil
+}
+
+type RegisterServiceClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes        []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterServiceClientRequest) Reset() {
+	*x = RegisterServiceClientRequest{}
+	mi := &file_rpc_service_client_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterServiceClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterServiceClientRequest) ProtoMessage() {}
+
+func (x *RegisterServiceClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_client_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterServiceClientRequest.ProtoReflect.Descriptor instead.
+func (*RegisterServiceClientRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_client_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RegisterServiceClientRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisterServiceClientRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+type RegisterServiceClientResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Client *ServiceClient         `protobuf:"bytes,1,opt,name=client,proto3" json:"client,omitempty"`
+	// показывается один раз, в базе хранится только хеш
+	ClientSecret  string `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterServiceClientResponse) Reset() {
+	*x = RegisterServiceClientResponse{}
+	mi := &file_rpc_service_client_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterServiceClientResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterServiceClientResponse) ProtoMessage() {}
+
+func (x *RegisterServiceClientResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_client_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterServiceClientResponse.ProtoReflect.Descriptor instead.
+func (*RegisterServiceClientResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_client_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RegisterServiceClientResponse) GetClient() *ServiceClient {
+	if x != nil {
+		return x.Client
+	}
+	return nil
+}
+
+func (x *RegisterServiceClientResponse) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+type RotateServiceClientSecretRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateServiceClientSecretRequest) Reset() {
+	*x = RotateServiceClientSecretRequest{}
+	mi := &file_rpc_service_client_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateServiceClientSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateServiceClientSecretRequest) ProtoMessage() {}
+
+func (x *RotateServiceClientSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_client_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateServiceClientSecretRequest.ProtoReflect.Descriptor instead.
+func (*RotateServiceClientSecretRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_client_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RotateServiceClientSecretRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type RotateServiceClientSecretResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Client        *ServiceClient         `protobuf:"bytes,1,opt,name=client,proto3" json:"client,omitempty"`
+	ClientSecret  string                 `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateServiceClientSecretResponse) Reset() {
+	*x = RotateServiceClientSecretResponse{}
+	mi := &file_rpc_service_client_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateServiceClientSecretResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateServiceClientSecretResponse) ProtoMessage() {}
+
+func (x *RotateServiceClientSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_client_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateServiceClientSecretResponse.ProtoReflect.Descriptor instead.
+func (*RotateServiceClientSecretResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_client_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RotateServiceClientSecretResponse) GetClient() *ServiceClient {
+	if x != nil {
+		return x.Client
+	}
+	return nil
+}
+
+func (x *RotateServiceClientSecretResponse) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+var File_rpc_service_client_proto protoreflect.FileDescriptor
+
+const file_rpc_service_client_proto_rawDesc = "" +
+	"\n" +
+	"\x18rpc_service_client.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xdb\x01\n" +
+	"\rServiceClient\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12F\n" +
+	"\x11secret_rotated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0fsecretRotatedAt\"J\n" +
+	"\x1cRegisterServiceClientRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x02 \x03(\tR\x06scopes\"o\n" +
+	"\x1dRegisterServiceClientResponse\x12)\n" +
+	"\x06client\x18\x01 \x01(\v2\x11.pb.ServiceClientR\x06client\x12#\n" +
+	"\rclient_secret\x18\x02 \x01(\tR\fclientSecret\"?\n" +
+	" RotateServiceClientSecretRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\"s\n" +
+	"!RotateServiceClientSecretResponse\x12)\n" +
+	"\x06client\x18\x01 \x01(\v2\x11.pb.ServiceClientR\x06client\x12#\n" +
+	"\rclient_secret\x18\x02 \x01(\tR\fclientSecretB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
+
+var (
+	file_rpc_service_client_proto_rawDescOnce sync.Once
+	file_rpc_service_client_proto_rawDescData []byte
+)
+
+func file_rpc_service_client_proto_rawDescGZIP() []byte {
+	file_rpc_service_client_proto_rawDescOnce.Do(func() {
+		file_rpc_service_client_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_service_client_proto_rawDesc), len(file_rpc_service_client_proto_rawDesc)))
+	})
+	return file_rpc_service_client_proto_rawDescData
+}
+
+var file_rpc_service_client_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_rpc_service_client_proto_goTypes = []any{
+	(*ServiceClient)(nil),                     // 0: pb.ServiceClient
+	(*RegisterServiceClientRequest)(nil),      // 1: pb.RegisterServiceClientRequest
+	(*RegisterServiceClientResponse)(nil),     // 2: pb.RegisterServiceClientResponse
+	(*RotateServiceClientSecretRequest)(nil),  // 3: pb.RotateServiceClientSecretRequest
+	(*RotateServiceClientSecretResponse)(nil), // 4: pb.RotateServiceClientSecretResponse
+	(*timestamppb.Timestamp)(nil),             // 5: google.protobuf.Timestamp
+}
+var file_rpc_service_client_proto_depIdxs = []int32{
+	5, // 0: pb.ServiceClient.created_at:type_name -> google.protobuf.Timestamp
+	5, // 1: pb.ServiceClient.secret_rotated_at:type_name -> google.protobuf.Timestamp
+	0, // 2: pb.RegisterServiceClientResponse.client:type_name -> pb.ServiceClient
+	0, // 3: pb.RotateServiceClientSecretResponse.client:type_name -> pb.ServiceClient
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_rpc_service_client_proto_init() }
+func file_rpc_service_client_proto_init() {
+	if File_rpc_service_client_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_service_client_proto_rawDesc), len(file_rpc_service_client_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_service_client_proto_goTypes,
+		DependencyIndexes: file_rpc_service_client_proto_depIdxs,
+		MessageInfos:      file_rpc_service_client_proto_msgTypes,
+	}.Build()
+	File_rpc_service_client_proto = out.File
+	file_rpc_service_client_proto_goTypes = nil
+	file_rpc_service_client_proto_depIdxs = nil
+}
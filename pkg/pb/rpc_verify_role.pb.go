@@ -21,8 +21,11 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// пустые role и permission - старое поведение: нужна роль admin или moderator
 type VerifyRoleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Permission    string                 `protobuf:"bytes,2,opt,name=permission,proto3" json:"permission,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -57,10 +60,26 @@ func (*VerifyRoleRequest) Descriptor() ([]byte, []int) {
 	return file_rpc_verify_role_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *VerifyRoleRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *VerifyRoleRequest) GetPermission() string {
+	if x != nil {
+		return x.Permission
+	}
+	return ""
+}
+
 type VerifyRoleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Error         bool                   `protobuf:"varint,1,opt,name=error,proto3" json:"error,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Roles         []string               `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
+	Permissions   []string               `protobuf:"bytes,4,rep,name=permissions,proto3" json:"permissions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -109,15 +128,35 @@ func (x *VerifyRoleResponse) GetMessage() string {
 	return ""
 }
 
+func (x *VerifyRoleResponse) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *VerifyRoleResponse) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
 var File_rpc_verify_role_proto protoreflect.FileDescriptor
 
 const file_rpc_verify_role_proto_rawDesc = "" +
 	"\n" +
-	"\x15rpc_verify_role.proto\x12\x02pb\"\x13\n" +
-	"\x11VerifyRoleRequest\"D\n" +
+	"\x15rpc_verify_role.proto\x12\x02pb\"G\n" +
+	"\x11VerifyRoleRequest\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x1e\n" +
+	"\n" +
+	"permission\x18\x02 \x01(\tR\n" +
+	"permission\"|\n" +
 	"\x12VerifyRoleResponse\x12\x14\n" +
 	"\x05error\x18\x01 \x01(\bR\x05error\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessageB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05roles\x18\x03 \x03(\tR\x05roles\x12 \n" +
+	"\vpermissions\x18\x04 \x03(\tR\vpermissionsB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
 
 var (
 	file_rpc_verify_role_proto_rawDescOnce sync.Once
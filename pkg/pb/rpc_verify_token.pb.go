@@ -22,7 +22,9 @@ const (
 )
 
 type VerifyTokenRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// токен другого приложения отклоняется
+	AppId         int64 `protobuf:"varint,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -57,10 +59,21 @@ func (*VerifyTokenRequest) Descriptor() ([]byte, []int) {
 	return file_rpc_verify_token_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *VerifyTokenRequest) GetAppId() int64 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
 type VerifyTokenResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         bool                   `protobuf:"varint,1,opt,name=error,proto3" json:"error,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Error   bool                   `protobuf:"varint,1,opt,name=error,proto3" json:"error,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// права токена: scopes personal access token'а или scope сессии
+	Scopes []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// заполнен для токенов сервисов (client_credentials); юзверя у них нет
+	ClientId      string `protobuf:"bytes,4,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -109,15 +122,32 @@ func (x *VerifyTokenResponse) GetMessage() string {
 	return ""
 }
 
+func (x *VerifyTokenResponse) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *VerifyTokenResponse) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
 var File_rpc_verify_token_proto protoreflect.FileDescriptor
 
 const file_rpc_verify_token_proto_rawDesc = "" +
 	"\n" +
-	"\x16rpc_verify_token.proto\x12\x02pb\"\x14\n" +
-	"\x12VerifyTokenRequest\"E\n" +
+	"\x16rpc_verify_token.proto\x12\x02pb\"+\n" +
+	"\x12VerifyTokenRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\x03R\x05appId\"z\n" +
 	"\x13VerifyTokenResponse\x12\x14\n" +
 	"\x05error\x18\x01 \x01(\bR\x05error\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessageB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12\x1b\n" +
+	"\tclient_id\x18\x04 \x01(\tR\bclientIdB*Z(github.com/Iowel/app-auth-service/pkg/pbb\x06proto3"
 
 var (
 	file_rpc_verify_token_proto_rawDescOnce sync.Once
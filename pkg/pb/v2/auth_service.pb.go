@@ -0,0 +1,449 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: v2/auth_service.proto
+
+package pbv2
+
+import (
+	pb "github.com/Iowel/app-auth-service/pkg/pb"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// значения ErrorInfo.reason; ErrorInfo.domain всегда "app-auth-service"
+type ErrorReason int32
+
+const (
+	ErrorReason_ERROR_REASON_UNSPECIFIED ErrorReason = 0
+	// InvalidArgument: app_id не передан, неизвестен или приложение выключено
+	ErrorReason_INVALID_APP_ID ErrorReason = 1
+	// AlreadyExists: email уже занят в этом приложении
+	ErrorReason_USER_EXISTS ErrorReason = 2
+	// Unauthenticated: неверный email или пароль
+	ErrorReason_WRONG_CREDENTIALS ErrorReason = 3
+	// FailedPrecondition: email не подтвержден
+	ErrorReason_EMAIL_NOT_VERIFIED ErrorReason = 4
+	// ResourceExhausted + RetryInfo: прогрессивная задержка после неудачных попыток
+	ErrorReason_TOO_MANY_ATTEMPTS ErrorReason = 5
+	// ResourceExhausted + RetryInfo: аккаунт или IP временно заблокированы
+	ErrorReason_ACCOUNT_LOCKED ErrorReason = 6
+	// PermissionDenied: токен выдан для другого приложения
+	ErrorReason_TOKEN_APP_MISMATCH ErrorReason = 7
+	// PermissionDenied: нет нужной роли или права, в metadata - что требовалось
+	ErrorReason_INSUFFICIENT_ROLE ErrorReason = 8
+)
+
+// Enum value maps for ErrorReason.
+var (
+	ErrorReason_name = map[int32]string{
+		0: "ERROR_REASON_UNSPECIFIED",
+		1: "INVALID_APP_ID",
+		2: "USER_EXISTS",
+		3: "WRONG_CREDENTIALS",
+		4: "EMAIL_NOT_VERIFIED",
+		5: "TOO_MANY_ATTEMPTS",
+		6: "ACCOUNT_LOCKED",
+		7: "TOKEN_APP_MISMATCH",
+		8: "INSUFFICIENT_ROLE",
+	}
+	ErrorReason_value = map[string]int32{
+		"ERROR_REASON_UNSPECIFIED": 0,
+		"INVALID_APP_ID":           1,
+		"USER_EXISTS":              2,
+		"WRONG_CREDENTIALS":        3,
+		"EMAIL_NOT_VERIFIED":       4,
+		"TOO_MANY_ATTEMPTS":        5,
+		"ACCOUNT_LOCKED":           6,
+		"TOKEN_APP_MISMATCH":       7,
+		"INSUFFICIENT_ROLE":        8,
+	}
+)
+
+func (x ErrorReason) Enum() *ErrorReason {
+	p := new(ErrorReason)
+	*p = x
+	return p
+}
+
+func (x ErrorReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_auth_service_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorReason) Type() protoreflect.EnumType {
+	return &file_v2_auth_service_proto_enumTypes[0]
+}
+
+func (x ErrorReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorReason.Descriptor instead.
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return file_v2_auth_service_proto_rawDescGZIP(), []int{0}
+}
+
+type RegisterUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterUserResponse) Reset() {
+	*x = RegisterUserResponse{}
+	mi := &file_v2_auth_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterUserResponse) ProtoMessage() {}
+
+func (x *RegisterUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_auth_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterUserResponse.ProtoReflect.Descriptor instead.
+func (*RegisterUserResponse) Descriptor() ([]byte, []int) {
+	return file_v2_auth_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterUserResponse) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// либо токены, либо two_factor_required с challenge_token для VerifyTwoFactor
+type LoginUserResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Token             *pb.Token              `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken      *pb.Token              `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	TwoFactorRequired bool                   `protobuf:"varint,3,opt,name=two_factor_required,json=twoFactorRequired,proto3" json:"two_factor_required,omitempty"`
+	ChallengeToken    string                 `protobuf:"bytes,4,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *LoginUserResponse) Reset() {
+	*x = LoginUserResponse{}
+	mi := &file_v2_auth_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginUserResponse) ProtoMessage() {}
+
+func (x *LoginUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_auth_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginUserResponse.ProtoReflect.Descriptor instead.
+func (*LoginUserResponse) Descriptor() ([]byte, []int) {
+	return file_v2_auth_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LoginUserResponse) GetToken() *pb.Token {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *LoginUserResponse) GetRefreshToken() *pb.Token {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return nil
+}
+
+func (x *LoginUserResponse) GetTwoFactorRequired() bool {
+	if x != nil {
+		return x.TwoFactorRequired
+	}
+	return false
+}
+
+func (x *LoginUserResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+// для токенов сервисов заполнен только client_id и scopes
+type VerifyTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	AppId         int64                  `protobuf:"varint,3,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Scopes        []string               `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	ClientId      string                 `protobuf:"bytes,5,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyTokenResponse) Reset() {
+	*x = VerifyTokenResponse{}
+	mi := &file_v2_auth_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTokenResponse) ProtoMessage() {}
+
+func (x *VerifyTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_auth_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTokenResponse.ProtoReflect.Descriptor instead.
+func (*VerifyTokenResponse) Descriptor() ([]byte, []int) {
+	return file_v2_auth_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VerifyTokenResponse) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *VerifyTokenResponse) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *VerifyTokenResponse) GetAppId() int64 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+func (x *VerifyTokenResponse) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *VerifyTokenResponse) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type VerifyRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Roles         []string               `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	Permissions   []string               `protobuf:"bytes,2,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyRoleResponse) Reset() {
+	*x = VerifyRoleResponse{}
+	mi := &file_v2_auth_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRoleResponse) ProtoMessage() {}
+
+func (x *VerifyRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_auth_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRoleResponse.ProtoReflect.Descriptor instead.
+func (*VerifyRoleResponse) Descriptor() ([]byte, []int) {
+	return file_v2_auth_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VerifyRoleResponse) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *VerifyRoleResponse) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+var File_v2_auth_service_proto protoreflect.FileDescriptor
+
+const file_v2_auth_service_proto_rawDesc = "" +
+	"\n" +
+	"\x15v2/auth_service.proto\x12\x05pb.v2\x1a\x17rpc_register_user.proto\x1a\x14rpc_login_user.proto\x1a\x16rpc_verify_token.proto\x1a\x15rpc_verify_role.proto\x1a\x1cgoogle/api/annotations.proto\"/\n" +
+	"\x14RegisterUserResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"\xbd\x01\n" +
+	"\x11LoginUserResponse\x12\x1f\n" +
+	"\x05token\x18\x01 \x01(\v2\t.pb.TokenR\x05token\x12.\n" +
+	"\rrefresh_token\x18\x02 \x01(\v2\t.pb.TokenR\frefreshToken\x12.\n" +
+	"\x13two_factor_required\x18\x03 \x01(\bR\x11twoFactorRequired\x12'\n" +
+	"\x0fchallenge_token\x18\x04 \x01(\tR\x0echallengeToken\"\x90\x01\n" +
+	"\x13VerifyTokenResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x15\n" +
+	"\x06app_id\x18\x03 \x01(\x03R\x05appId\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x12\x1b\n" +
+	"\tclient_id\x18\x05 \x01(\tR\bclientId\"L\n" +
+	"\x12VerifyRoleResponse\x12\x14\n" +
+	"\x05roles\x18\x01 \x03(\tR\x05roles\x12 \n" +
+	"\vpermissions\x18\x02 \x03(\tR\vpermissions*\xd9\x01\n" +
+	"\vErrorReason\x12\x1c\n" +
+	"\x18ERROR_REASON_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eINVALID_APP_ID\x10\x01\x12\x0f\n" +
+	"\vUSER_EXISTS\x10\x02\x12\x15\n" +
+	"\x11WRONG_CREDENTIALS\x10\x03\x12\x16\n" +
+	"\x12EMAIL_NOT_VERIFIED\x10\x04\x12\x15\n" +
+	"\x11TOO_MANY_ATTEMPTS\x10\x05\x12\x12\n" +
+	"\x0eACCOUNT_LOCKED\x10\x06\x12\x16\n" +
+	"\x12TOKEN_APP_MISMATCH\x10\a\x12\x15\n" +
+	"\x11INSUFFICIENT_ROLE\x10\b2\x85\x03\n" +
+	"\vAuthService\x12b\n" +
+	"\fRegisterUser\x12\x17.pb.RegisterUserRequest\x1a\x1b.pb.v2.RegisterUserResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v2/register_user\x12V\n" +
+	"\tLoginUser\x12\x14.pb.LoginUserRequest\x1a\x18.pb.v2.LoginUserResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v2/login_user\x12^\n" +
+	"\vVerifyToken\x12\x16.pb.VerifyTokenRequest\x1a\x1a.pb.v2.VerifyTokenResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v2/verify_token\x12Z\n" +
+	"\n" +
+	"VerifyRole\x12\x15.pb.VerifyRoleRequest\x1a\x19.pb.v2.VerifyRoleResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v2/verify_roleB2Z0github.com/Iowel/app-auth-service/pkg/pb/v2;pbv2b\x06proto3"
+
+var (
+	file_v2_auth_service_proto_rawDescOnce sync.Once
+	file_v2_auth_service_proto_rawDescData []byte
+)
+
+func file_v2_auth_service_proto_rawDescGZIP() []byte {
+	file_v2_auth_service_proto_rawDescOnce.Do(func() {
+		file_v2_auth_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_v2_auth_service_proto_rawDesc), len(file_v2_auth_service_proto_rawDesc)))
+	})
+	return file_v2_auth_service_proto_rawDescData
+}
+
+var file_v2_auth_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_v2_auth_service_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_v2_auth_service_proto_goTypes = []any{
+	(ErrorReason)(0),               // 0: pb.v2.ErrorReason
+	(*RegisterUserResponse)(nil),   // 1: pb.v2.RegisterUserResponse
+	(*LoginUserResponse)(nil),      // 2: pb.v2.LoginUserResponse
+	(*VerifyTokenResponse)(nil),    // 3: pb.v2.VerifyTokenResponse
+	(*VerifyRoleResponse)(nil),     // 4: pb.v2.VerifyRoleResponse
+	(*pb.Token)(nil),               // 5: pb.Token
+	(*pb.RegisterUserRequest)(nil), // 6: pb.RegisterUserRequest
+	(*pb.LoginUserRequest)(nil),    // 7: pb.LoginUserRequest
+	(*pb.VerifyTokenRequest)(nil),  // 8: pb.VerifyTokenRequest
+	(*pb.VerifyRoleRequest)(nil),   // 9: pb.VerifyRoleRequest
+}
+var file_v2_auth_service_proto_depIdxs = []int32{
+	5, // 0: pb.v2.LoginUserResponse.token:type_name -> pb.Token
+	5, // 1: pb.v2.LoginUserResponse.refresh_token:type_name -> pb.Token
+	6, // 2: pb.v2.AuthService.RegisterUser:input_type -> pb.RegisterUserRequest
+	7, // 3: pb.v2.AuthService.LoginUser:input_type -> pb.LoginUserRequest
+	8, // 4: pb.v2.AuthService.VerifyToken:input_type -> pb.VerifyTokenRequest
+	9, // 5: pb.v2.AuthService.VerifyRole:input_type -> pb.VerifyRoleRequest
+	1, // 6: pb.v2.AuthService.RegisterUser:output_type -> pb.v2.RegisterUserResponse
+	2, // 7: pb.v2.AuthService.LoginUser:output_type -> pb.v2.LoginUserResponse
+	3, // 8: pb.v2.AuthService.VerifyToken:output_type -> pb.v2.VerifyTokenResponse
+	4, // 9: pb.v2.AuthService.VerifyRole:output_type -> pb.v2.VerifyRoleResponse
+	6, // [6:10] is the sub-list for method output_type
+	2, // [2:6] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_v2_auth_service_proto_init() }
+func file_v2_auth_service_proto_init() {
+	if File_v2_auth_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v2_auth_service_proto_rawDesc), len(file_v2_auth_service_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_v2_auth_service_proto_goTypes,
+		DependencyIndexes: file_v2_auth_service_proto_depIdxs,
+		EnumInfos:         file_v2_auth_service_proto_enumTypes,
+		MessageInfos:      file_v2_auth_service_proto_msgTypes,
+	}.Build()
+	File_v2_auth_service_proto = out.File
+	file_v2_auth_service_proto_goTypes = nil
+	file_v2_auth_service_proto_depIdxs = nil
+}